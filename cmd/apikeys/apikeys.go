@@ -0,0 +1,103 @@
+package apikeys
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/foomo/contentful"
+
+	"github.com/foomo/contentfulcommander/contentfulclient"
+)
+
+func Run(cma *contentful.Contentful, params []string) error {
+	flagSet := flag.NewFlagSet("apikeys "+params[0], flag.ContinueOnError)
+	description := flagSet.String("description", "", "description of the API key")
+	environments := flagSet.String("environments", "", "comma separated list of environments the API key can access")
+	err := flagSet.Parse(params[1:])
+	if err != nil {
+		return err
+	}
+	args := flagSet.Args()
+	if len(args) == 0 {
+		return errors.New("apikeys needs a space parameter")
+	}
+	// API keys belong to the space, the environment is the default for the environments of new keys
+	spaceID, environment := contentfulclient.GetSpaceAndEnvironment(args[0])
+	ctx := context.Background()
+	operation := contentfulclient.OperationRead
+	if params[0] == "create" || params[0] == "update" {
+		operation = contentfulclient.OperationUpdate
+	}
+	err = contentfulclient.PreflightSpace(ctx, cma, spaceID, operation)
+	if err != nil {
+		return err
+	}
+	switch params[0] {
+	case "list":
+		apiKeys, err := contentfulclient.ListAPIKeys(ctx, cma, spaceID)
+		if err != nil {
+			return err
+		}
+		for _, apiKey := range apiKeys {
+			printAPIKey(apiKey, false)
+		}
+		return nil
+	case "create":
+		if len(args) != 2 {
+			return errors.New("apikeys create needs space and name")
+		}
+		if *environments == "" {
+			*environments = environment
+		}
+		apiKey := &contentfulclient.APIKey{
+			Name:         args[1],
+			Description:  *description,
+			Environments: contentfulclient.EnvironmentLinks(strings.Split(*environments, ",")),
+		}
+		err := contentfulclient.UpsertAPIKey(ctx, cma, spaceID, apiKey)
+		if err != nil {
+			return err
+		}
+		log.Printf("API key %s was created", apiKey.Sys.ID)
+		printAPIKey(*apiKey, true)
+		return nil
+	case "update":
+		if len(args) != 2 {
+			return errors.New("apikeys update needs space and API key ID")
+		}
+		apiKey, err := contentfulclient.GetAPIKey(ctx, cma, spaceID, args[1])
+		if err != nil {
+			return err
+		}
+		if *description != "" {
+			apiKey.Description = *description
+		}
+		if *environments != "" {
+			apiKey.Environments = contentfulclient.EnvironmentLinks(strings.Split(*environments, ","))
+		}
+		err = contentfulclient.UpsertAPIKey(ctx, cma, spaceID, apiKey)
+		if err != nil {
+			return err
+		}
+		log.Printf("API key %s was updated", apiKey.Sys.ID)
+		printAPIKey(*apiKey, false)
+		return nil
+	default:
+		return fmt.Errorf("unknown apikeys subcommand %q, use list, create or update", params[0])
+	}
+}
+
+func printAPIKey(apiKey contentfulclient.APIKey, withToken bool) {
+	environments := make([]string, 0, len(apiKey.Environments))
+	for _, environment := range apiKey.Environments {
+		environments = append(environments, environment.Sys.ID)
+	}
+	fmt.Printf("%s %q environments: %s\n", apiKey.Sys.ID, apiKey.Name, strings.Join(environments, ","))
+	if withToken {
+		fmt.Printf("    delivery access token: %s\n", apiKey.AccessToken)
+	}
+}
@@ -0,0 +1,97 @@
+package apps
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/foomo/contentful"
+
+	"github.com/foomo/contentfulcommander/contentfulclient"
+)
+
+func Run(cma *contentful.Contentful, params []string) error {
+	spaceID, environment := contentfulclient.GetSpaceAndEnvironment(params[1])
+	ctx := context.Background()
+	operation := contentfulclient.OperationRead
+	if params[0] == "install" || params[0] == "uninstall" {
+		operation = contentfulclient.OperationApps
+	}
+	err := contentfulclient.Preflight(ctx, cma, spaceID, environment, operation)
+	if err != nil {
+		return err
+	}
+	switch params[0] {
+	case "list":
+		installations, err := contentfulclient.ListAppInstallations(ctx, cma, spaceID, environment)
+		if err != nil {
+			return err
+		}
+		for _, installation := range installations {
+			parameters := string(installation.Parameters)
+			if parameters == "" {
+				parameters = "{}"
+			}
+			fmt.Printf("%s %s\n", installation.Sys.AppDefinition.Sys.ID, parameters)
+		}
+		return nil
+	case "definitions":
+		organizationID, err := contentfulclient.GetOrganizationID(ctx, cma, spaceID)
+		if err != nil {
+			return fmt.Errorf("could not get the organization of space %s: %v", spaceID, err)
+		}
+		definitions, err := contentfulclient.ListAppDefinitions(ctx, cma, organizationID)
+		if err != nil {
+			return err
+		}
+		for _, definition := range definitions {
+			fmt.Printf("%s %q %s\n", definition.Sys.ID, definition.Name, definition.SrcURL)
+		}
+		return nil
+	case "install":
+		if len(params) != 3 && len(params) != 4 {
+			return errors.New("apps install needs space, app definition ID and optionally a parameters file")
+		}
+		var parameters json.RawMessage
+		if len(params) == 4 {
+			parameters, err = readParameters(params[3])
+			if err != nil {
+				return err
+			}
+		}
+		_, err := contentfulclient.InstallApp(ctx, cma, spaceID, environment, params[2], parameters)
+		if err != nil {
+			return err
+		}
+		log.Printf("App %s was installed in %s/%s", params[2], spaceID, environment)
+		return nil
+	case "uninstall":
+		if len(params) != 3 {
+			return errors.New("apps uninstall needs space and app definition ID")
+		}
+		err := contentfulclient.UninstallApp(ctx, cma, spaceID, environment, params[2])
+		if err != nil {
+			return err
+		}
+		log.Printf("App %s was uninstalled from %s/%s", params[2], spaceID, environment)
+		return nil
+	default:
+		return fmt.Errorf("unknown apps subcommand %q, use list, definitions, install or uninstall", params[0])
+	}
+}
+
+func readParameters(fileName string) (json.RawMessage, error) {
+	byt, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	var parameters map[string]any
+	err = json.Unmarshal(byt, &parameters)
+	if err != nil {
+		return nil, fmt.Errorf("could not read app parameters from %s: %v", fileName, err)
+	}
+	return byt, nil
+}
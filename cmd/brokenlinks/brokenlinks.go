@@ -0,0 +1,96 @@
+package brokenlinks
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"sort"
+
+	"github.com/foomo/contentful"
+
+	"github.com/foomo/contentfulcommander/cmd/common"
+	"github.com/foomo/contentfulcommander/contentfulclient"
+	"github.com/foomo/contentfulcommander/model"
+)
+
+type brokenLink struct {
+	entryID    string
+	previewURL string
+	locale     string
+	reference  model.ReferenceSysAttributes
+}
+
+func Run(cma *contentful.Contentful, params []string) error {
+	flagSet := flag.NewFlagSet("brokenlinks", flag.ContinueOnError)
+	contentTypeID := flagSet.String("contenttype", "", "only check entries of this content type")
+	err := flagSet.Parse(params)
+	if err != nil {
+		return err
+	}
+	if flagSet.NArg() != 1 {
+		return errors.New("brokenlinks needs exactly one space parameter")
+	}
+	spaceID, environment := contentfulclient.GetSpaceAndEnvironment(flagSet.Arg(0))
+	cma.Environment = environment
+	err = contentfulclient.Preflight(context.Background(), cma, spaceID, environment, contentfulclient.OperationRead)
+	if err != nil {
+		return err
+	}
+	// links can point to entries of any content type, so all entries are loaded
+	entries, err := common.GetAllEntries(cma, spaceID)
+	if err != nil {
+		return err
+	}
+	assets, err := common.GetAllAssets(cma, spaceID)
+	if err != nil {
+		return err
+	}
+	log.Printf("Loaded %d entries and %d assets, checking links", len(entries), len(assets))
+	existing := map[string]map[string]bool{"Entry": {}, "Asset": {}}
+	for _, entry := range entries {
+		existing["Entry"][entry.Sys.ID] = true
+	}
+	for _, asset := range assets {
+		existing["Asset"][asset.Sys.ID] = true
+	}
+
+	brokenByField := map[string][]brokenLink{}
+	for _, entry := range entries {
+		if *contentTypeID != "" && entry.Sys.ContentType.Sys.ID != *contentTypeID {
+			continue
+		}
+		common.WalkReferences(entry, func(fieldID, locale string, reference model.ReferenceSysAttributes) {
+			if existing[reference.LinkType][reference.ID] {
+				return
+			}
+			key := entry.Sys.ContentType.Sys.ID + "." + fieldID
+			brokenByField[key] = append(brokenByField[key], brokenLink{
+				entryID:    entry.Sys.ID,
+				previewURL: common.PreviewURL(entry),
+				locale:     locale,
+				reference:  reference,
+			})
+		})
+	}
+	keys := make([]string, 0, len(brokenByField))
+	total := 0
+	for key, links := range brokenByField {
+		keys = append(keys, key)
+		total += len(links)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Printf("%s\n", key)
+		for _, link := range brokenByField[key] {
+			fmt.Printf("    %s [%s] links to missing %s %s https://app.contentful.com/spaces/%s/environments/%s/entries/%s\n",
+				link.entryID, link.locale, link.reference.LinkType, link.reference.ID, spaceID, environment, link.entryID)
+			if link.previewURL != "" {
+				fmt.Printf("        preview: %s\n", link.previewURL)
+			}
+		}
+	}
+	log.Printf("Found %d broken links in %d fields", total, len(keys))
+	return nil
+}
@@ -0,0 +1,204 @@
+package chid
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/foomo/contentful"
+
+	"github.com/foomo/contentfulcommander/cmd/common"
+)
+
+// runBatch changes the IDs of all pairs in the CSV file. All entries are loaded once to find the parents
+// of every old ID, so each parent is updated only once even if it links to several renamed entries.
+func runBatch(cma *contentful.Contentful, spaceID, filename string) error {
+	renames, err := readRenames(filename)
+	if err != nil {
+		return err
+	}
+	entries, err := common.GetAllEntries(cma, spaceID)
+	if err != nil {
+		return err
+	}
+	entryMap := make(map[string]*contentful.Entry, len(entries))
+	for _, entry := range entries {
+		entryMap[entry.Sys.ID] = entry
+	}
+	for oldID, newID := range renames {
+		if _, ok := entryMap[oldID]; !ok {
+			return fmt.Errorf("entry %s does not exist", oldID)
+		}
+		if _, ok := entryMap[newID]; ok {
+			return fmt.Errorf("an entry with the new ID %s already exists", newID)
+		}
+	}
+	log.Printf("Changing the IDs of %d entries", len(renames))
+
+	// the copies are created with the fields of the old entries as they are. References are only changed
+	// afterwards, once it is known which copies exist.
+	failed := 0
+	var created []*contentful.Entry
+	for oldID, newID := range renames {
+		oldEntry := entryMap[oldID]
+		fields, err := copyFields(oldEntry.Fields)
+		if err != nil {
+			return err
+		}
+		newEntry := &contentful.Entry{
+			Fields: fields,
+			Sys: &contentful.Sys{
+				ID: newID,
+				ContentType: &contentful.ContentType{
+					Sys: &contentful.Sys{
+						ID:       oldEntry.Sys.ContentType.Sys.ID,
+						Type:     "Link",
+						LinkType: "ContentType",
+					},
+				},
+			},
+		}
+		err = common.SmartUpdateEntry(newEntry, oldEntry, cma, spaceID)
+		if err != nil {
+			log.Printf("New entry %s could not be created: %v", newID, err)
+			failed++
+			continue
+		}
+		created = append(created, oldEntry)
+	}
+	// only references to entries that were copied successfully may be changed. The parents are the entries
+	// that stay, including the copies and the old entries whose copy failed.
+	createdRenames := make(map[string]string, len(created))
+	for _, oldEntry := range created {
+		createdRenames[oldEntry.Sys.ID] = renames[oldEntry.Sys.ID]
+	}
+	var parents []*contentful.Entry
+	for _, entry := range entries {
+		newID, ok := createdRenames[entry.Sys.ID]
+		if !ok {
+			parents = append(parents, entry)
+			continue
+		}
+		// the copy has the fields of the old entry, they tell if it links to other renamed entries
+		parents = append(parents, &contentful.Entry{Sys: &contentful.Sys{ID: newID}, Fields: entry.Fields})
+	}
+	for _, parent := range parents {
+		fields, err := copyFields(parent.Fields)
+		if err != nil {
+			return err
+		}
+		changed, err := replaceReferences(&contentful.Entry{Sys: parent.Sys, Fields: fields}, createdRenames)
+		if err != nil {
+			return err
+		}
+		if !changed {
+			continue
+		}
+		err = relink(cma, spaceID, parent.Sys.ID, createdRenames)
+		if err != nil {
+			log.Printf("Parent entry %s could not be updated: %v", parent.Sys.ID, err)
+			failed++
+		}
+	}
+	for _, oldEntry := range created {
+		err := retire(cma, spaceID, oldEntry.Sys.ID)
+		if err != nil {
+			log.Printf("Old entry %s could not be archived: %v", oldEntry.Sys.ID, err)
+			failed++
+			continue
+		}
+		log.Printf("Entry %s is now %s, the old entry was archived", oldEntry.Sys.ID, renames[oldEntry.Sys.ID])
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of the changes failed, see the log above", failed)
+	}
+	log.Print("All done.")
+	return nil
+}
+
+// relink loads the entry again as it may have been created or published in this run and changes its
+// references to the new IDs
+func relink(cma *contentful.Contentful, spaceID, entryID string, renames map[string]string) error {
+	entry, err := cma.Entries.Get(spaceID, entryID)
+	if err != nil {
+		return err
+	}
+	if entry == nil {
+		return errors.New("entry could not be loaded")
+	}
+	_, err = replaceReferences(entry, renames)
+	if err != nil {
+		return err
+	}
+	return common.SmartUpdateEntry(entry, nil, cma, spaceID)
+}
+
+// copyFields returns a deep copy, so references can be changed without touching the loaded entry
+func copyFields(fields map[string]any) (map[string]any, error) {
+	bytes, err := json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+	var copied map[string]any
+	err = json.Unmarshal(bytes, &copied)
+	return copied, err
+}
+
+// readRenames reads old ID and new ID pairs, lines starting with # are skipped
+func readRenames(filename string) (map[string]string, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	reader := csv.NewReader(file)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = 2
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("could not read %s: %v", filename, err)
+	}
+	renames := map[string]string{}
+	newIDs := map[string]bool{}
+	for _, record := range records {
+		oldID, newID := strings.TrimSpace(record[0]), strings.TrimSpace(record[1])
+		if _, ok := renames[oldID]; ok {
+			return nil, fmt.Errorf("entry %s is listed twice in %s", oldID, filename)
+		}
+		if newIDs[newID] {
+			return nil, fmt.Errorf("new ID %s is listed twice in %s", newID, filename)
+		}
+		renames[oldID] = newID
+		newIDs[newID] = true
+	}
+	return renames, nil
+}
+
+func retire(cma *contentful.Contentful, spaceID, entryID string) error {
+	entry, err := cma.Entries.Get(spaceID, entryID)
+	if err != nil {
+		return err
+	}
+	if entry == nil {
+		return errors.New("entry could not be loaded")
+	}
+	if entry.Sys.PublishedVersion > 0 {
+		err = cma.Entries.Unpublish(spaceID, entry)
+		if err != nil {
+			return err
+		}
+		// Unpublish does not return the new version
+		entry, err = cma.Entries.Get(spaceID, entryID)
+		if err != nil {
+			return err
+		}
+		if entry == nil {
+			return errors.New("entry could not be loaded after unpublishing")
+		}
+	}
+	return cma.Entries.Archive(spaceID, entry)
+}
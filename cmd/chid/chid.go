@@ -1,7 +1,11 @@
 package chid
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
 	"log"
 
 	"github.com/foomo/contentful"
@@ -12,16 +16,44 @@ import (
 )
 
 func Run(cma *contentful.Contentful, params []string) error {
+	flagSet := flag.NewFlagSet("chid", flag.ContinueOnError)
+	file := flagSet.String("file", "", "CSV file with old ID and new ID pairs to change in one run")
+	err := flagSet.Parse(params)
+	if err != nil {
+		return err
+	}
+	params = flagSet.Args()
+	if *file != "" && len(params) != 1 {
+		return errors.New("chid with a file needs exactly one space parameter")
+	}
+	if *file == "" && len(params) != 3 {
+		return errors.New("chid needs a space, the old ID and the new ID")
+	}
 	spaceID, environment := contentfulclient.GetSpaceAndEnvironment(params[0])
 	cma.Environment = environment
+	err = contentfulclient.Preflight(context.Background(), cma, spaceID, environment,
+		contentfulclient.OperationUpdate, contentfulclient.OperationPublish, contentfulclient.OperationArchive)
+	if err != nil {
+		return err
+	}
+	if *file != "" {
+		return runBatch(cma, spaceID, *file)
+	}
 	oldID := params[1]
 	newID := params[2]
 	oldEntry, err := cma.Entries.Get(spaceID, oldID)
 	if err != nil {
-		log.Fatal("Could not get old entry from space")
+		return fmt.Errorf("could not get old entry from space: %v", err)
+	}
+	if oldEntry == nil {
+		return fmt.Errorf("entry %s does not exist", oldID)
+	}
+	exists, err := common.EntryExistsByID(cma, spaceID, newID)
+	if err != nil {
+		return err
 	}
-	if common.EntryExistsByID(cma, spaceID, newID) {
-		log.Fatal("An entry with the new ID supplied already exists")
+	if exists {
+		return errors.New("an entry with the new ID supplied already exists")
 	}
 	newEntry := &contentful.Entry{}
 	newEntry.Fields = oldEntry.Fields
@@ -46,62 +78,17 @@ func Run(cma *contentful.Contentful, params []string) error {
 	}
 	parentNeedsUpdate := map[string]*contentful.Entry{}
 	for _, parent := range parents {
-		for fieldName, field := range parent.Fields {
-			bytes, err := json.Marshal(field)
-			if err != nil {
-				return err
-			}
-			// Try single reference
-			singleRefLocalized := map[string]model.ReferenceSys{}
-			err = json.Unmarshal(bytes, &singleRefLocalized)
-			if err == nil {
-				for locale, referenceSys := range singleRefLocalized {
-					if referenceSys.Sys.ID == oldID {
-						log.Printf("Found a reference in entry %s and field %s", parent.Sys.ID, fieldName)
-						newReferenceSys := model.ReferenceSys{
-							Sys: model.ReferenceSysAttributes{
-								ID:       newID,
-								Type:     "Link",
-								LinkType: "Entry",
-							},
-						}
-						singleRefLocalized[locale] = newReferenceSys
-						parent.Fields[fieldName] = singleRefLocalized
-						parentNeedsUpdate[parent.Sys.ID] = parent
-					}
-				}
-			}
-			// Try multiple references
-			multiRefLocalized := map[string][]model.ReferenceSys{}
-			err = json.Unmarshal(bytes, &multiRefLocalized)
-			if err == nil {
-				for locale, referenceSysSlice := range multiRefLocalized {
-					var newReferenceSysMap []model.ReferenceSys
-					for _, referenceSys := range referenceSysSlice {
-						if referenceSys.Sys.ID == oldID {
-							log.Printf("Found a reference in entry %s and field %s", parent.Sys.ID, fieldName)
-							newReferenceSys := model.ReferenceSys{
-								Sys: model.ReferenceSysAttributes{
-									ID:       newID,
-									Type:     "Link",
-									LinkType: "Entry",
-								},
-							}
-							newReferenceSysMap = append(newReferenceSysMap, newReferenceSys)
-							parent.Fields[fieldName] = multiRefLocalized
-							parentNeedsUpdate[parent.Sys.ID] = parent
-						} else {
-							newReferenceSysMap = append(newReferenceSysMap, referenceSys)
-						}
-					}
-					multiRefLocalized[locale] = newReferenceSysMap
-				}
-			}
+		changed, err := replaceReferences(parent, map[string]string{oldID: newID})
+		if err != nil {
+			return err
+		}
+		if changed {
+			parentNeedsUpdate[parent.Sys.ID] = parent
 		}
 	}
 	err = common.SmartUpdateEntry(newEntry, oldEntry, cma, spaceID)
 	if err != nil {
-		log.Fatalf("New entry error in smart update: %v", err)
+		return fmt.Errorf("new entry error in smart update: %v", err)
 	}
 	for _, parent := range parentNeedsUpdate {
 		err := common.SmartUpdateEntry(parent, nil, cma, spaceID)
@@ -113,20 +100,75 @@ func Run(cma *contentful.Contentful, params []string) error {
 	log.Printf("Old entry: https://app.contentful.com/spaces/%s/environments/%s/entries/%s", spaceID, cma.Environment, oldEntry.Sys.ID)
 	oldEntry, err = cma.Entries.Get(spaceID, oldEntry.Sys.ID)
 	if err != nil {
-		log.Fatalf("Error getting old entry for unpublishing: %v", err)
+		return fmt.Errorf("error getting old entry for unpublishing: %v", err)
 	}
 	err = cma.Entries.Unpublish(spaceID, oldEntry)
 	if err != nil {
-		log.Fatalf("Error unpublishing old entry: %v", err)
+		return fmt.Errorf("error unpublishing old entry: %v", err)
 	}
 	oldEntry, err = cma.Entries.Get(spaceID, oldEntry.Sys.ID)
 	if err != nil {
-		log.Fatalf("Error getting old entry for archiving: %v", err)
+		return fmt.Errorf("error getting old entry for archiving: %v", err)
 	}
 	err = cma.Entries.Archive(spaceID, oldEntry)
 	if err != nil {
-		log.Fatalf("Error archiving old entry: %v", err)
+		return fmt.Errorf("error archiving old entry: %v", err)
 	}
 	log.Print("Old entry was archived. All done.")
 	return nil
 }
+
+// replaceReferences changes single and multiple reference fields of an entry linking to an old ID
+// in renames to link to the new ID and returns true if any field was changed
+func replaceReferences(parent *contentful.Entry, renames map[string]string) (bool, error) {
+	changed := false
+	for fieldName, field := range parent.Fields {
+		bytes, err := json.Marshal(field)
+		if err != nil {
+			return false, err
+		}
+		// Try single reference
+		singleRefLocalized := map[string]model.ReferenceSys{}
+		err = json.Unmarshal(bytes, &singleRefLocalized)
+		if err == nil {
+			for locale, referenceSys := range singleRefLocalized {
+				if newID, ok := renames[referenceSys.Sys.ID]; ok {
+					log.Printf("Found a reference in entry %s and field %s", parent.Sys.ID, fieldName)
+					singleRefLocalized[locale] = newEntryReference(newID)
+					parent.Fields[fieldName] = singleRefLocalized
+					changed = true
+				}
+			}
+		}
+		// Try multiple references
+		multiRefLocalized := map[string][]model.ReferenceSys{}
+		err = json.Unmarshal(bytes, &multiRefLocalized)
+		if err == nil {
+			for locale, referenceSysSlice := range multiRefLocalized {
+				var newReferenceSysMap []model.ReferenceSys
+				for _, referenceSys := range referenceSysSlice {
+					if newID, ok := renames[referenceSys.Sys.ID]; ok {
+						log.Printf("Found a reference in entry %s and field %s", parent.Sys.ID, fieldName)
+						newReferenceSysMap = append(newReferenceSysMap, newEntryReference(newID))
+						parent.Fields[fieldName] = multiRefLocalized
+						changed = true
+					} else {
+						newReferenceSysMap = append(newReferenceSysMap, referenceSys)
+					}
+				}
+				multiRefLocalized[locale] = newReferenceSysMap
+			}
+		}
+	}
+	return changed, nil
+}
+
+func newEntryReference(id string) model.ReferenceSys {
+	return model.ReferenceSys{
+		Sys: model.ReferenceSysAttributes{
+			ID:       id,
+			Type:     "Link",
+			LinkType: "Entry",
+		},
+	}
+}
@@ -0,0 +1,148 @@
+package churn
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/foomo/contentful"
+
+	"github.com/foomo/contentfulcommander/cmd/common"
+	"github.com/foomo/contentfulcommander/contentfulclient"
+)
+
+type bucket struct {
+	label string
+	upTo  time.Duration
+}
+
+var buckets = []bucket{
+	{"< 1 hour", time.Hour},
+	{"< 1 day", 24 * time.Hour},
+	{"< 1 week", 7 * 24 * time.Hour},
+	{"< 30 days", 30 * 24 * time.Hour},
+	{"< 1 year", 365 * 24 * time.Hour},
+	{">= 1 year", 0},
+}
+
+type contentTypeChurn struct {
+	entries   int
+	edits     int
+	publishes int
+	versions  []int
+	// histogram counts entries by the mean time between their edits, entries never edited after
+	// creation are counted in neverEdited
+	histogram   []int
+	neverEdited int
+}
+
+func Run(cma *contentful.Contentful, params []string) error {
+	flagSet := flag.NewFlagSet("churn", flag.ContinueOnError)
+	contentTypeID := flagSet.String("contenttype", "", "only look at entries of this content type")
+	err := flagSet.Parse(params)
+	if err != nil {
+		return err
+	}
+	if flagSet.NArg() != 1 {
+		return errors.New("churn needs exactly one space parameter")
+	}
+	spaceID, environment := contentfulclient.GetSpaceAndEnvironment(flagSet.Arg(0))
+	cma.Environment = environment
+	err = contentfulclient.Preflight(context.Background(), cma, spaceID, environment, contentfulclient.OperationRead)
+	if err != nil {
+		return err
+	}
+	// only the sys of each entry is needed, so the entries are not kept in memory
+	churnByContentType := map[string]*contentTypeChurn{}
+	total := 0
+	err = common.ForEachEntry(cma, spaceID, *contentTypeID, func(entry *contentful.Entry) error {
+		id := entry.Sys.ContentType.Sys.ID
+		churn, ok := churnByContentType[id]
+		if !ok {
+			churn = &contentTypeChurn{histogram: make([]int, len(buckets))}
+			churnByContentType[id] = churn
+		}
+		churn.add(entry.Sys)
+		total++
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	log.Printf("Loaded %d entries", total)
+	printReport(churnByContentType)
+	return nil
+}
+
+// add counts an entry. Every edit and every publish increases the version by one, so the number of
+// edits is what is left of the version after the creation and the publishes.
+func (c *contentTypeChurn) add(sys *contentful.Sys) {
+	edits := sys.Version - 1 - sys.PublishedCounter
+	if edits < 0 {
+		edits = 0
+	}
+	c.entries++
+	c.edits += edits
+	c.publishes += sys.PublishedCounter
+	c.versions = append(c.versions, sys.Version)
+	createdAt, errCreated := time.Parse(time.RFC3339, sys.CreatedAt)
+	updatedAt, errUpdated := time.Parse(time.RFC3339, sys.UpdatedAt)
+	if edits == 0 || errCreated != nil || errUpdated != nil {
+		c.neverEdited++
+		return
+	}
+	meanInterval := updatedAt.Sub(createdAt) / time.Duration(edits)
+	for i, b := range buckets {
+		if b.upTo == 0 || meanInterval < b.upTo {
+			c.histogram[i]++
+			return
+		}
+	}
+}
+
+func printReport(churnByContentType map[string]*contentTypeChurn) {
+	contentTypeIDs := make([]string, 0, len(churnByContentType))
+	for id := range churnByContentType {
+		contentTypeIDs = append(contentTypeIDs, id)
+	}
+	// the most volatile content types come first
+	sort.Slice(contentTypeIDs, func(i, j int) bool {
+		a, b := churnByContentType[contentTypeIDs[i]], churnByContentType[contentTypeIDs[j]]
+		return float64(a.edits)/float64(a.entries) > float64(b.edits)/float64(b.entries)
+	})
+	for _, id := range contentTypeIDs {
+		churn := churnByContentType[id]
+		sort.Ints(churn.versions)
+		fmt.Printf("Content type: %s\n", id)
+		fmt.Printf("    Entries:               %8d\n", churn.entries)
+		fmt.Printf("    Edits per entry:       %8.1f\n", float64(churn.edits)/float64(churn.entries))
+		fmt.Printf("    Publishes per entry:   %8.1f\n", float64(churn.publishes)/float64(churn.entries))
+		fmt.Printf("    Median version:        %8d\n", churn.versions[len(churn.versions)/2])
+		fmt.Printf("    Max version:           %8d\n", churn.versions[len(churn.versions)-1])
+		fmt.Println("    Mean time between edits:")
+		maxCount := churn.neverEdited
+		for _, count := range churn.histogram {
+			if count > maxCount {
+				maxCount = count
+			}
+		}
+		for i, b := range buckets {
+			printBar(b.label, churn.histogram[i], maxCount)
+		}
+		printBar("never", churn.neverEdited, maxCount)
+	}
+}
+
+func printBar(label string, count, maxCount int) {
+	const width = 40
+	length := 0
+	if maxCount > 0 {
+		length = count * width / maxCount
+	}
+	fmt.Printf("        %-10s %8d %s\n", label, count, strings.Repeat("#", length))
+}
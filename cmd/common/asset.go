@@ -0,0 +1,333 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/foomo/contentful"
+
+	"github.com/foomo/contentfulcommander/contentfulclient"
+)
+
+const assetPollInterval = time.Second
+
+func GetAllAssets(cma *contentful.Contentful, spaceID string) ([]*contentful.Asset, error) {
+	collection, err := contentfulclient.GetAll(func() *contentful.Collection {
+		return cma.Assets.List(spaceID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return contentfulclient.DecodeItems[*contentful.Asset](collection)
+}
+
+// GetAssetsModifiedSince returns the assets that were updated after since
+func GetAssetsModifiedSince(cma *contentful.Contentful, spaceID string, since time.Time) ([]*contentful.Asset, error) {
+	collection, err := contentfulclient.GetAll(func() *contentful.Collection {
+		collection := cma.Assets.List(spaceID)
+		collection.Query.GreaterThan("sys.updatedAt", since.UTC())
+		return collection
+	})
+	if err != nil {
+		return nil, err
+	}
+	return contentfulclient.DecodeItems[*contentful.Asset](collection)
+}
+
+// SmartUpdateAsset updates an asset and publishes it again if it was published without pending changes
+// before the update
+func SmartUpdateAsset(asset *contentful.Asset, cma *contentful.Contentful, spaceID string) error {
+	if asset == nil {
+		return errors.New("asset is nil")
+	}
+	wasPublished := asset.Sys.Version-asset.Sys.PublishedVersion == 1
+	err := cma.Assets.Upsert(spaceID, asset)
+	if err != nil {
+		return err
+	}
+	log.Printf("Asset %s was updated", asset.Sys.ID)
+	if !wasPublished {
+		log.Printf("Asset %s didn't need re-publishing", asset.Sys.ID)
+		return nil
+	}
+	err = cma.Assets.Publish(spaceID, asset)
+	if err != nil {
+		return err
+	}
+	log.Printf("Asset %s was re-published", asset.Sys.ID)
+	return nil
+}
+
+// IsAssetProcessed is true once the files of all locales have been processed and have a URL
+func IsAssetProcessed(asset *contentful.Asset) bool {
+	if asset.Fields == nil || len(asset.Fields.File) == 0 {
+		return false
+	}
+	for _, file := range asset.Fields.File {
+		if file == nil || file.URL == "" {
+			return false
+		}
+	}
+	return true
+}
+
+// WaitForAssetProcessed polls an asset until it is processed, which is required before it can be published
+func WaitForAssetProcessed(ctx context.Context, cma *contentful.Contentful, spaceID, assetID string,
+	timeout time.Duration,
+) (*contentful.Asset, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	ticker := time.NewTicker(assetPollInterval)
+	defer ticker.Stop()
+	for {
+		asset, err := cma.Assets.Get(spaceID, assetID)
+		if err != nil {
+			return nil, err
+		}
+		if IsAssetProcessed(asset) {
+			return asset, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("asset %s was not processed in time: %v", assetID, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// WaitForAssetsProcessed waits for a batch of assets with at most concurrency assets polled at the same time
+func WaitForAssetsProcessed(ctx context.Context, cma *contentful.Contentful, spaceID string, assetIDs []string,
+	timeout time.Duration, concurrency int,
+) error {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		failed    int
+		firstErr  error
+		semaphore = make(chan struct{}, concurrency)
+	)
+	for _, assetID := range assetIDs {
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func(assetID string) {
+			defer func() {
+				<-semaphore
+				wg.Done()
+			}()
+			_, err := WaitForAssetProcessed(ctx, cma, spaceID, assetID, timeout)
+			if err != nil {
+				mu.Lock()
+				failed++
+				if firstErr == nil {
+					firstErr = err
+				}
+				mu.Unlock()
+			}
+		}(assetID)
+	}
+	wg.Wait()
+	if failed > 0 {
+		return fmt.Errorf("%d of %d assets were not processed, first error: %v", failed, len(assetIDs), firstErr)
+	}
+	return nil
+}
+
+// AssetFile is the title and file of an asset in one locale. The file is either fetched by Contentful from
+// URL or taken from an upload of the upload API.
+type AssetFile struct {
+	Title       string
+	FileName    string
+	ContentType string
+	URL         string
+	UploadID    string
+}
+
+// CreateAsset creates an asset with a file per locale, processes the files and waits until they are
+// processed. The asset is published if publish is set. Without assetID Contentful generates an ID.
+func CreateAsset(ctx context.Context, cma *contentful.Contentful, spaceID, assetID string, files map[string]AssetFile,
+	publish bool, timeout time.Duration,
+) (*contentful.Asset, error) {
+	titles := map[string]string{}
+	fileFields := map[string]any{}
+	for locale, file := range files {
+		titles[locale] = file.Title
+		fileField := map[string]any{"fileName": file.FileName, "contentType": file.ContentType}
+		if file.UploadID != "" {
+			fileField["uploadFrom"] = map[string]any{
+				"sys": map[string]string{"type": "Link", "linkType": "Upload", "id": file.UploadID},
+			}
+		} else {
+			fileField["upload"] = file.URL
+		}
+		fileFields[locale] = fileField
+	}
+	payload := map[string]any{"fields": map[string]any{"title": titles, "file": fileFields}}
+	var created struct {
+		Sys struct {
+			ID      string `json:"id"`
+			Version int    `json:"version"`
+		} `json:"sys"`
+	}
+	assetPath := fmt.Sprintf("/spaces/%s/environments/%s/assets", spaceID, cma.Environment)
+	method := http.MethodPost
+	if assetID != "" {
+		assetPath += "/" + assetID
+		method = http.MethodPut
+	}
+	err := contentfulclient.Do(ctx, cma, method, assetPath, payload, &created)
+	if err != nil {
+		return nil, fmt.Errorf("could not create asset: %v", err)
+	}
+	assetPath = fmt.Sprintf("/spaces/%s/environments/%s/assets/%s", spaceID, cma.Environment, created.Sys.ID)
+	for locale := range files {
+		err = contentfulclient.DoWithHeaders(ctx, cma, http.MethodPut, fmt.Sprintf("%s/files/%s/process", assetPath, locale),
+			map[string]string{"X-Contentful-Version": strconv.Itoa(created.Sys.Version)}, nil, nil)
+		if err != nil {
+			return nil, fmt.Errorf("could not process the file of asset %s for locale %s: %v", created.Sys.ID, locale, err)
+		}
+	}
+	asset, err := WaitForAssetProcessed(ctx, cma, spaceID, created.Sys.ID, timeout)
+	if err != nil {
+		return nil, err
+	}
+	if !publish {
+		return asset, nil
+	}
+	err = cma.Assets.Publish(spaceID, asset)
+	if err != nil {
+		return nil, fmt.Errorf("could not publish asset %s: %v", asset.Sys.ID, err)
+	}
+	return asset, nil
+}
+
+// CreateAssetFromFile uploads a local file and creates an asset from it in one locale
+func CreateAssetFromFile(ctx context.Context, cma *contentful.Contentful, spaceID, assetID, locale, title, fileName string,
+	publish bool, timeout time.Duration,
+) (*contentful.Asset, error) {
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	uploadID, err := contentfulclient.Upload(ctx, cma, spaceID, data)
+	if err != nil {
+		return nil, fmt.Errorf("could not upload %s: %v", fileName, err)
+	}
+	contentType := mime.TypeByExtension(filepath.Ext(fileName))
+	if contentType == "" {
+		contentType = http.DetectContentType(data)
+	}
+	file := AssetFile{Title: title, FileName: filepath.Base(fileName), ContentType: contentType, UploadID: uploadID}
+	return CreateAsset(ctx, cma, spaceID, assetID, map[string]AssetFile{locale: file}, publish, timeout)
+}
+
+// CreateAssetFromURL creates an asset in one locale from a file Contentful downloads from fileURL
+func CreateAssetFromURL(ctx context.Context, cma *contentful.Contentful, spaceID, assetID, locale, title, fileURL string,
+	publish bool, timeout time.Duration,
+) (*contentful.Asset, error) {
+	parsedURL, err := url.Parse(fileURL)
+	if err != nil {
+		return nil, err
+	}
+	fileName := path.Base(parsedURL.Path)
+	contentType := mime.TypeByExtension(path.Ext(fileName))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	file := AssetFile{Title: title, FileName: fileName, ContentType: contentType, URL: fileURL}
+	return CreateAsset(ctx, cma, spaceID, assetID, map[string]AssetFile{locale: file}, publish, timeout)
+}
+
+// DownloadAssets downloads the files of all locales of the assets to dir/locale/assetid-filename with at
+// most concurrency downloads at the same time. Files that already exist are skipped, so an interrupted
+// download can be continued. It returns the number of downloaded files.
+func DownloadAssets(ctx context.Context, assets []*contentful.Asset, dir string, concurrency int) (int, error) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	var (
+		wg         sync.WaitGroup
+		mu         sync.Mutex
+		downloaded int
+		failed     int
+		total      int
+		firstErr   error
+		semaphore  = make(chan struct{}, concurrency)
+	)
+	for _, asset := range assets {
+		if asset.Fields == nil {
+			continue
+		}
+		for locale, file := range asset.Fields.File {
+			if file == nil || file.URL == "" {
+				continue
+			}
+			total++
+			fileName := filepath.Join(dir, locale, asset.Sys.ID+"-"+filepath.Base(file.Name))
+			wg.Add(1)
+			semaphore <- struct{}{}
+			go func(fileURL, fileName string) {
+				defer func() {
+					<-semaphore
+					wg.Done()
+				}()
+				ok, err := downloadFile(ctx, fileURL, fileName)
+				mu.Lock()
+				defer mu.Unlock()
+				if err != nil {
+					failed++
+					if firstErr == nil {
+						firstErr = err
+					}
+					return
+				}
+				if ok {
+					downloaded++
+				}
+			}(file.URL, fileName)
+		}
+	}
+	wg.Wait()
+	if failed > 0 {
+		return downloaded, fmt.Errorf("%d of %d files could not be downloaded, first error: %v", failed, total, firstErr)
+	}
+	return downloaded, nil
+}
+
+// downloadFile writes to a temporary file first, so that failed downloads do not leave partial files
+func downloadFile(ctx context.Context, fileURL, fileName string) (bool, error) {
+	if _, err := os.Stat(fileName); err == nil {
+		return false, nil
+	}
+	err := os.MkdirAll(filepath.Dir(fileName), 0o755)
+	if err != nil {
+		return false, err
+	}
+	file, err := os.Create(fileName + ".part")
+	if err != nil {
+		return false, err
+	}
+	err = contentfulclient.Download(ctx, fileURL, file)
+	closeErr := file.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(fileName + ".part")
+		return false, err
+	}
+	return true, os.Rename(fileName+".part", fileName)
+}
@@ -0,0 +1,34 @@
+package common
+
+import (
+	"github.com/foomo/contentfulcommander/model"
+)
+
+// GetEntryLinkValidation returns the allowed content types of Link and Array of Link fields to entries,
+// an empty list means that any content type can be linked
+func GetEntryLinkValidation(field model.ContentTypeField) ([]string, bool) {
+	if field.Type == "Array" && field.Items != nil && field.Items.LinkType == "Entry" {
+		var allowed []string
+		for _, validation := range field.Items.Validations {
+			allowed = append(allowed, validation.LinkContentType...)
+		}
+		return allowed, true
+	}
+	if field.Type != "Link" || field.LinkType != "Entry" {
+		return nil, false
+	}
+	var allowed []string
+	for _, validation := range field.Validations {
+		validationMap, ok := validation.(map[string]any)
+		if !ok {
+			continue
+		}
+		linkContentTypes, _ := validationMap["linkContentType"].([]any)
+		for _, linkContentType := range linkContentTypes {
+			if id, ok := linkContentType.(string); ok {
+				allowed = append(allowed, id)
+			}
+		}
+	}
+	return allowed, true
+}
@@ -2,28 +2,33 @@ package common
 
 import (
 	"errors"
+	"fmt"
 	"log"
+	"time"
 
 	"github.com/foomo/contentful"
+
+	"github.com/foomo/contentfulcommander/contentfulclient"
 )
 
-func EntryExistsByID(cma *contentful.Contentful, spaceID, entryID string) bool {
+func EntryExistsByID(cma *contentful.Contentful, spaceID, entryID string) (bool, error) {
 	entry, err := cma.Entries.Get(spaceID, entryID)
 	if err != nil {
-		log.Fatalf("could not check if new entry ID is already taken: %v", err)
+		return false, fmt.Errorf("could not check if entry ID %s is already taken: %v", entryID, err)
 	}
-	return entry != nil
+	return entry != nil, nil
 }
 
 func GetEntriesLinkingToThis(cma *contentful.Contentful, spaceID, entryID string) ([]*contentful.Entry, error) {
-	collection := cma.Entries.List(spaceID)
-	collection.Query.Equal("links_to_entry", entryID)
-	var err error
-	collection, err = collection.GetAll()
+	collection, err := contentfulclient.GetAll(func() *contentful.Collection {
+		collection := cma.Entries.List(spaceID)
+		collection.Query.Equal("links_to_entry", entryID)
+		return collection
+	})
 	if err != nil {
 		return nil, err
 	}
-	return collection.ToEntry(), nil
+	return contentfulclient.DecodeItems[*contentful.Entry](collection)
 }
 
 func SmartUpdateEntry(entry *contentful.Entry, refEntry *contentful.Entry, cma *contentful.Contentful, spaceID string) error {
@@ -58,3 +63,67 @@ func SmartUpdateEntry(entry *contentful.Entry, refEntry *contentful.Entry, cma *
 	log.Printf("Entry %s didn't need re-publishing", entry.Sys.ID)
 	return nil
 }
+
+func GetEntriesByContentType(cma *contentful.Contentful, spaceID, contentTypeID string) ([]*contentful.Entry, error) {
+	collection, err := contentfulclient.GetAll(func() *contentful.Collection {
+		collection := cma.Entries.List(spaceID)
+		collection.Query.ContentType(contentTypeID)
+		return collection
+	})
+	if err != nil {
+		return nil, err
+	}
+	return contentfulclient.DecodeItems[*contentful.Entry](collection)
+}
+
+func GetAllEntries(cma *contentful.Contentful, spaceID string) ([]*contentful.Entry, error) {
+	return GetEntriesByContentType(cma, spaceID, "")
+}
+
+// GetEntriesModifiedSince returns the entries of a content type, or of all content types if contentTypeID
+// is empty, that were updated after since, so that incremental jobs only have to load the changes
+func GetEntriesModifiedSince(cma *contentful.Contentful, spaceID, contentTypeID string, since time.Time) ([]*contentful.Entry, error) {
+	collection, err := contentfulclient.GetAll(func() *contentful.Collection {
+		collection := cma.Entries.List(spaceID)
+		collection.Query.ContentType(contentTypeID)
+		// the contentful package formats times without a zone, which the API reads as UTC
+		collection.Query.GreaterThan("sys.updatedAt", since.UTC())
+		return collection
+	})
+	if err != nil {
+		return nil, err
+	}
+	return contentfulclient.DecodeItems[*contentful.Entry](collection)
+}
+
+// IsPublished is true if the latest version of an entity is the published one
+func IsPublished(sys *contentful.Sys) bool {
+	return sys.PublishedVersion > 0 && sys.Version-sys.PublishedVersion == 1
+}
+
+// IsChanged is true if an entity is published but has a newer draft
+func IsChanged(sys *contentful.Sys) bool {
+	return sys.PublishedVersion > 0 && sys.Version-sys.PublishedVersion > 1
+}
+
+// ForEachEntry calls fn for every entry of a content type, or of all content types if contentTypeID is
+// empty, loading and decoding the entries one page at a time
+func ForEachEntry(cma *contentful.Contentful, spaceID, contentTypeID string, fn func(entry *contentful.Entry) error) error {
+	return contentfulclient.GetPages(func() *contentful.Collection {
+		collection := cma.Entries.List(spaceID)
+		collection.Query.ContentType(contentTypeID)
+		return collection
+	}, func(page *contentful.Collection) error {
+		entries, err := contentfulclient.DecodeItems[*contentful.Entry](page)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			err = fn(entry)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,70 @@
+package common
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+
+	"github.com/foomo/contentful"
+)
+
+var (
+	previewURLTemplate string
+	previewLocale      string
+	previewPlaceholder = regexp.MustCompile(`\{(id|contentType|locale|fields\.[A-Za-z0-9_]+)\}`)
+)
+
+// SetPreviewURL configures the template for PreviewURL. The placeholders {id}, {contentType} and {locale}
+// are replaced with the entry ID, its content type and the locale, {fields.slug} with the value of the
+// field slug in that locale. Without a locale the first locale of the entry is used.
+func SetPreviewURL(template, locale string) {
+	previewURLTemplate = template
+	previewLocale = locale
+}
+
+// PreviewURL renders the preview URL of an entry for reports, it is empty if no template is configured
+func PreviewURL(entry *contentful.Entry) string {
+	if previewURLTemplate == "" {
+		return ""
+	}
+	locale := previewLocale
+	if locale == "" {
+		locale = getFirstLocale(entry)
+	}
+	return previewPlaceholder.ReplaceAllStringFunc(previewURLTemplate, func(placeholder string) string {
+		name := placeholder[1 : len(placeholder)-1]
+		switch name {
+		case "id":
+			return url.PathEscape(entry.Sys.ID)
+		case "contentType":
+			return url.PathEscape(entry.Sys.ContentType.Sys.ID)
+		case "locale":
+			return url.PathEscape(locale)
+		}
+		localizedValue, _ := entry.Fields[name[len("fields."):]].(map[string]any)
+		if value, ok := localizedValue[locale]; ok {
+			// slugs may contain slashes for nested paths, so they are not escaped
+			return fmt.Sprint(value)
+		}
+		return ""
+	})
+}
+
+func getFirstLocale(entry *contentful.Entry) string {
+	var locales []string
+	for _, field := range entry.Fields {
+		localizedValue, ok := field.(map[string]any)
+		if !ok {
+			continue
+		}
+		for locale := range localizedValue {
+			locales = append(locales, locale)
+		}
+	}
+	sort.Strings(locales)
+	if len(locales) == 0 {
+		return ""
+	}
+	return locales[0]
+}
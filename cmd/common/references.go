@@ -0,0 +1,129 @@
+package common
+
+import (
+	"github.com/foomo/contentful"
+
+	"github.com/foomo/contentfulcommander/model"
+)
+
+// WalkReferences calls fn for every link in the fields of an entry, including links to entries and
+// assets embedded in RichText documents
+func WalkReferences(entry *contentful.Entry, fn func(fieldID, locale string, reference model.ReferenceSysAttributes)) {
+	for fieldID, field := range entry.Fields {
+		localizedValue, ok := field.(map[string]any)
+		if !ok {
+			continue
+		}
+		for locale, value := range localizedValue {
+			walkValue(value, func(reference model.ReferenceSysAttributes) {
+				fn(fieldID, locale, reference)
+			})
+		}
+	}
+}
+
+// GetOutboundReferences returns every entry and asset an entry links to, once per linked entity
+func GetOutboundReferences(entry *contentful.Entry) []model.ReferenceSysAttributes {
+	seen := map[model.ReferenceSysAttributes]bool{}
+	var references []model.ReferenceSysAttributes
+	WalkReferences(entry, func(_, _ string, reference model.ReferenceSysAttributes) {
+		if !seen[reference] {
+			seen[reference] = true
+			references = append(references, reference)
+		}
+	})
+	return references
+}
+
+func walkValue(value any, fn func(reference model.ReferenceSysAttributes)) {
+	switch v := value.(type) {
+	case map[string]any:
+		if reference, ok := getLink(v); ok {
+			fn(reference)
+			return
+		}
+		for _, child := range v {
+			walkValue(child, fn)
+		}
+	case []any:
+		for _, child := range v {
+			walkValue(child, fn)
+		}
+	}
+}
+
+func getLink(value map[string]any) (model.ReferenceSysAttributes, bool) {
+	sys, ok := value["sys"].(map[string]any)
+	if !ok || sys["type"] != "Link" {
+		return model.ReferenceSysAttributes{}, false
+	}
+	id, _ := sys["id"].(string)
+	linkType, _ := sys["linkType"].(string)
+	if linkType != "Entry" && linkType != "Asset" {
+		return model.ReferenceSysAttributes{}, false
+	}
+	return model.ReferenceSysAttributes{ID: id, Type: "Link", LinkType: linkType}, true
+}
+
+// SortByDependencies orders entries so that entries come after the entries they link to, which lets
+// a tree of entries be published leaves first. Links to entries outside the list are ignored and
+// cycles are broken in list order.
+func SortByDependencies(entries []*contentful.Entry) []*contentful.Entry {
+	byID := make(map[string]*contentful.Entry, len(entries))
+	for _, entry := range entries {
+		byID[entry.Sys.ID] = entry
+	}
+	sorted := make([]*contentful.Entry, 0, len(entries))
+	visited := make(map[string]bool, len(entries))
+	var visit func(entry *contentful.Entry)
+	visit = func(entry *contentful.Entry) {
+		if visited[entry.Sys.ID] {
+			return
+		}
+		visited[entry.Sys.ID] = true
+		for _, reference := range GetOutboundReferences(entry) {
+			if child, ok := byID[reference.ID]; ok && reference.LinkType == "Entry" {
+				visit(child)
+			}
+		}
+		sorted = append(sorted, entry)
+	}
+	for _, entry := range entries {
+		visit(entry)
+	}
+	return sorted
+}
+
+// RelinkReferences changes every link of an entry to the entity from into a link to the entity to, both of
+// the same link type, optionally only in the field fieldID. It returns the number of changed links.
+func RelinkReferences(entry *contentful.Entry, from model.ReferenceSysAttributes, toID, fieldID string) int {
+	changed := 0
+	for id, field := range entry.Fields {
+		if fieldID == "" || id == fieldID {
+			changed += relinkValue(field, from, toID)
+		}
+	}
+	return changed
+}
+
+func relinkValue(value any, from model.ReferenceSysAttributes, toID string) int {
+	changed := 0
+	switch v := value.(type) {
+	case map[string]any:
+		if reference, ok := getLink(v); ok {
+			if reference.ID == from.ID && reference.LinkType == from.LinkType {
+				v["sys"].(map[string]any)["id"] = toID
+				changed++
+			}
+			return changed
+		}
+		for _, child := range v {
+			changed += relinkValue(child, from, toID)
+		}
+	case []any:
+		for _, child := range v {
+			changed += relinkValue(child, from, toID)
+		}
+	}
+	return changed
+}
@@ -0,0 +1,141 @@
+package common
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/foomo/contentful"
+
+	"github.com/foomo/contentfulcommander/model"
+)
+
+const resourceURNPrefix = "crn:contentful:::content:"
+
+// entryNodeTypes are the RichText node types for entry links that replace the node types for resource links
+var entryNodeTypes = map[string]string{
+	"embedded-resource-block":  "embedded-entry-block",
+	"embedded-resource-inline": "embedded-entry-inline",
+	"resource-hyperlink":       "entry-hyperlink",
+}
+
+// ResourceURN is the parsed URN of a cross-space reference, Environment is empty if the URN has none
+type ResourceURN struct {
+	SpaceID     string
+	Environment string
+	EntryID     string
+}
+
+func ParseResourceURN(urn string) (ResourceURN, error) {
+	var parsed ResourceURN
+	if !strings.HasPrefix(urn, resourceURNPrefix) {
+		return parsed, fmt.Errorf("unsupported resource URN %q", urn)
+	}
+	parts := strings.Split(strings.TrimPrefix(urn, resourceURNPrefix), "/")
+	switch {
+	case len(parts) == 4 && parts[0] == "spaces" && parts[2] == "entries":
+		parsed.SpaceID, parsed.EntryID = parts[1], parts[3]
+	case len(parts) == 6 && parts[0] == "spaces" && parts[2] == "environments" && parts[4] == "entries":
+		parsed.SpaceID, parsed.Environment, parsed.EntryID = parts[1], parts[3], parts[5]
+	default:
+		return parsed, fmt.Errorf("unsupported resource URN %q", urn)
+	}
+	return parsed, nil
+}
+
+// WalkResourceLinks calls fn for every cross-space reference in the fields of an entry
+func WalkResourceLinks(entry *contentful.Entry, fn func(fieldID, locale string, link model.ResourceLink)) {
+	for fieldID, field := range entry.Fields {
+		localizedValue, ok := field.(map[string]any)
+		if !ok {
+			continue
+		}
+		for locale, value := range localizedValue {
+			walkResourceLinks(value, func(link map[string]any) any {
+				fn(fieldID, locale, toResourceLink(link))
+				return nil
+			})
+		}
+	}
+}
+
+// LocalizeResourceLinks replaces all cross-space references to entries of spaceID with plain entry
+// references, as needed when the content of that space has been merged into the space of the entry.
+// The fields have to be turned into reference fields in the content model before the entry is saved.
+// RichText nodes of replaced links become the matching entry nodes.
+func LocalizeResourceLinks(entry *contentful.Entry, spaceID string) (replaced int) {
+	for fieldID, field := range entry.Fields {
+		localizedValue, ok := field.(map[string]any)
+		if !ok {
+			continue
+		}
+		for locale, value := range localizedValue {
+			localizedValue[locale] = walkResourceLinks(value, func(link map[string]any) any {
+				urn, err := ParseResourceURN(toResourceLink(link).Sys.URN)
+				if err != nil || urn.SpaceID != spaceID {
+					return nil
+				}
+				replaced++
+				return model.ReferenceSys{
+					Sys: model.ReferenceSysAttributes{
+						ID:       urn.EntryID,
+						Type:     "Link",
+						LinkType: "Entry",
+					},
+				}
+			})
+			localizeResourceNodes(localizedValue[locale])
+		}
+		entry.Fields[fieldID] = localizedValue
+	}
+	return replaced
+}
+
+// walkResourceLinks calls fn for every resource link in value and replaces the link with the result of
+// fn unless that is nil
+func walkResourceLinks(value any, fn func(link map[string]any) any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		if sys, ok := v["sys"].(map[string]any); ok && sys["type"] == "ResourceLink" {
+			if replacement := fn(v); replacement != nil {
+				return replacement
+			}
+			return v
+		}
+		for key, child := range v {
+			v[key] = walkResourceLinks(child, fn)
+		}
+	case []any:
+		for i, child := range v {
+			v[i] = walkResourceLinks(child, fn)
+		}
+	}
+	return value
+}
+
+// localizeResourceNodes changes the node type of RichText resource nodes whose target is an entry link
+func localizeResourceNodes(value any) {
+	switch v := value.(type) {
+	case map[string]any:
+		if nodeType, ok := v["nodeType"].(string); ok && entryNodeTypes[nodeType] != "" {
+			if data, ok := v["data"].(map[string]any); ok {
+				if _, ok := data["target"].(model.ReferenceSys); ok {
+					v["nodeType"] = entryNodeTypes[nodeType]
+				}
+			}
+		}
+		for _, child := range v {
+			localizeResourceNodes(child)
+		}
+	case []any:
+		for _, child := range v {
+			localizeResourceNodes(child)
+		}
+	}
+}
+
+func toResourceLink(link map[string]any) model.ResourceLink {
+	sys, _ := link["sys"].(map[string]any)
+	linkType, _ := sys["linkType"].(string)
+	urn, _ := sys["urn"].(string)
+	return model.ResourceLink{Sys: model.ResourceLinkSysAttributes{Type: "ResourceLink", LinkType: linkType, URN: urn}}
+}
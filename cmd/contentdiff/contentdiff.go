@@ -0,0 +1,172 @@
+package contentdiff
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/foomo/contentful"
+
+	"github.com/foomo/contentfulcommander/cmd/common"
+	"github.com/foomo/contentfulcommander/contentfulclient"
+)
+
+const maxValueLength = 120
+
+func Run(cma *contentful.Contentful, params []string) error {
+	flagSet := flag.NewFlagSet("contentdiff", flag.ContinueOnError)
+	contentTypeID := flagSet.String("contenttype", "", "only compare entries of this content type")
+	entryIDs := flagSet.String("ids", "", "comma separated list of entry IDs to compare")
+	err := flagSet.Parse(params)
+	if err != nil {
+		return err
+	}
+	if flagSet.NArg() != 2 {
+		return errors.New("contentdiff needs exactly two space parameters")
+	}
+	firstSpace, firstEnvironment := contentfulclient.GetSpaceAndEnvironment(flagSet.Arg(0))
+	secondSpace, secondEnvironment := contentfulclient.GetSpaceAndEnvironment(flagSet.Arg(1))
+	var onlyIDs map[string]bool
+	if *entryIDs != "" {
+		onlyIDs = map[string]bool{}
+		for _, id := range strings.Split(*entryIDs, ",") {
+			onlyIDs[strings.TrimSpace(id)] = true
+		}
+	}
+	firstEntries, err := getEntries(cma, firstSpace, firstEnvironment, *contentTypeID, onlyIDs)
+	if err != nil {
+		return err
+	}
+	secondEntries, err := getEntries(cma, secondSpace, secondEnvironment, *contentTypeID, onlyIDs)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("A: %s/%s B: %s/%s\n", firstSpace, firstEnvironment, secondSpace, secondEnvironment)
+
+	ids := map[string]bool{}
+	for id := range firstEntries {
+		ids[id] = true
+	}
+	for id := range secondEntries {
+		ids[id] = true
+	}
+	sortedIDs := make([]string, 0, len(ids))
+	for id := range ids {
+		sortedIDs = append(sortedIDs, id)
+	}
+	sort.Strings(sortedIDs)
+	different := 0
+	for _, id := range sortedIDs {
+		firstEntry, inFirst := firstEntries[id]
+		secondEntry, inSecond := secondEntries[id]
+		switch {
+		case !inSecond:
+			fmt.Printf("Entry: '%s' (%s)\n    AAA ___ entry only available in A\n", id, firstEntry.Sys.ContentType.Sys.ID)
+			different++
+		case !inFirst:
+			fmt.Printf("Entry: '%s' (%s)\n    ___ BBB entry only available in B\n", id, secondEntry.Sys.ContentType.Sys.ID)
+			different++
+		default:
+			if diffEntry(firstEntry, secondEntry) {
+				different++
+			}
+		}
+	}
+	log.Printf("Compared %d entries, %d are different", len(sortedIDs), different)
+	return nil
+}
+
+func getEntries(cma *contentful.Contentful, spaceID, environment, contentTypeID string, onlyIDs map[string]bool) (map[string]*contentful.Entry, error) {
+	cma.Environment = environment
+	err := contentfulclient.Preflight(context.Background(), cma, spaceID, environment, contentfulclient.OperationRead)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := common.GetEntriesByContentType(cma, spaceID, contentTypeID)
+	if err != nil {
+		return nil, fmt.Errorf("could not get entries for %s/%s: %v", spaceID, environment, err)
+	}
+	entryMap := make(map[string]*contentful.Entry, len(entries))
+	for _, entry := range entries {
+		if onlyIDs == nil || onlyIDs[entry.Sys.ID] {
+			entryMap[entry.Sys.ID] = entry
+		}
+	}
+	return entryMap, nil
+}
+
+// diffEntry prints the differences of all fields and locales and returns true if there are any
+func diffEntry(firstEntry, secondEntry *contentful.Entry) bool {
+	headerPrinted := false
+	printHeader := func() {
+		if !headerPrinted {
+			fmt.Printf("Entry: '%s' (%s)\n", firstEntry.Sys.ID, firstEntry.Sys.ContentType.Sys.ID)
+			headerPrinted = true
+		}
+	}
+	if firstEntry.Sys.ContentType.Sys.ID != secondEntry.Sys.ContentType.Sys.ID {
+		printHeader()
+		fmt.Printf("    AAA BBB content type is different: A %s, B %s\n",
+			firstEntry.Sys.ContentType.Sys.ID, secondEntry.Sys.ContentType.Sys.ID)
+	}
+	firstValues := getLocalizedValues(firstEntry)
+	secondValues := getLocalizedValues(secondEntry)
+	keys := map[string]bool{}
+	for key := range firstValues {
+		keys[key] = true
+	}
+	for key := range secondValues {
+		keys[key] = true
+	}
+	sortedKeys := make([]string, 0, len(keys))
+	for key := range keys {
+		sortedKeys = append(sortedKeys, key)
+	}
+	sort.Strings(sortedKeys)
+	for _, key := range sortedKeys {
+		firstValue, inFirst := firstValues[key]
+		secondValue, inSecond := secondValues[key]
+		switch {
+		case !inSecond:
+			printHeader()
+			fmt.Printf("    AAA ___ %s only set in A\n", key)
+		case !inFirst:
+			printHeader()
+			fmt.Printf("    ___ BBB %s only set in B\n", key)
+		case firstValue != secondValue:
+			printHeader()
+			fmt.Printf("    AAA BBB %s is different\n", key)
+			fmt.Printf("     ^   ^----B: %s\n", truncate(secondValue))
+			fmt.Printf("     ^--------A: %s\n", truncate(firstValue))
+		}
+	}
+	return headerPrinted
+}
+
+// getLocalizedValues returns the JSON encoded value of every field and locale keyed by field[locale]
+func getLocalizedValues(entry *contentful.Entry) map[string]string {
+	values := map[string]string{}
+	for fieldID, field := range entry.Fields {
+		localizedValue, ok := field.(map[string]any)
+		if !ok {
+			continue
+		}
+		for locale, value := range localizedValue {
+			byt, _ := json.Marshal(value)
+			values[fmt.Sprintf("%s[%s]", fieldID, locale)] = string(byt)
+		}
+	}
+	return values
+}
+
+func truncate(value string) string {
+	if len(value) <= maxValueLength {
+		return value
+	}
+	return value[:maxValueLength] + "..."
+}
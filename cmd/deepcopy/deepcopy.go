@@ -0,0 +1,395 @@
+package deepcopy
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/foomo/contentful"
+
+	"github.com/foomo/contentfulcommander/cmd/common"
+	"github.com/foomo/contentfulcommander/contentfulclient"
+	"github.com/foomo/contentfulcommander/model"
+)
+
+const (
+	ExistingNewID  = "newid"
+	ExistingLink   = "link"
+	ExistingUpdate = "update"
+)
+
+const assetProcessingTimeout = 5 * time.Minute
+
+// target is where an entry or asset of the source ends up, copy is false for entities that already exist
+// in the target and are only linked
+type target struct {
+	id      string
+	version int
+	copy    bool
+}
+
+// copier keeps the state of one copy run, the source entities are loaded first and the client is switched
+// to the target environment afterwards. Entities that could not be copied are removed from targets and
+// kept in failedSources, references lists the source links of every copy by target ID.
+type copier struct {
+	ctx           context.Context
+	cma           *contentful.Contentful
+	targetSpace   string
+	existing      string
+	publish       bool
+	entries       []*contentful.Entry
+	assets        []*contentful.Asset
+	targets       map[model.ReferenceSysAttributes]*target
+	failed        int
+	failedSources map[model.ReferenceSysAttributes]bool
+	references    map[string][]model.ReferenceSysAttributes
+	publishedIDs  map[string]bool
+}
+
+func Run(cma *contentful.Contentful, params []string) error {
+	flagSet := flag.NewFlagSet("deepcopy", flag.ContinueOnError)
+	existing := flagSet.String("existing", ExistingNewID, "what to do with IDs taken in the target: newid, link or update")
+	noPublish := flagSet.Bool("nopublish", false, "leave all copies in draft instead of keeping the publishing status")
+	err := flagSet.Parse(params)
+	if err != nil {
+		return err
+	}
+	if flagSet.NArg() < 3 {
+		return errors.New("deepcopy needs a source space, a target space and at least one entry ID")
+	}
+	if *existing != ExistingNewID && *existing != ExistingLink && *existing != ExistingUpdate {
+		return fmt.Errorf("unknown value %s for existing, use %s, %s or %s", *existing, ExistingNewID, ExistingLink, ExistingUpdate)
+	}
+	sourceSpace, sourceEnvironment := contentfulclient.GetSpaceAndEnvironment(flagSet.Arg(0))
+	targetSpace, targetEnvironment := contentfulclient.GetSpaceAndEnvironment(flagSet.Arg(1))
+	if sourceSpace == targetSpace && sourceEnvironment == targetEnvironment {
+		return errors.New("source and target are the same, use chid to copy entries within an environment")
+	}
+	ctx := context.Background()
+	err = contentfulclient.Preflight(ctx, cma, sourceSpace, sourceEnvironment, contentfulclient.OperationRead)
+	if err != nil {
+		return err
+	}
+	operations := []contentfulclient.Operation{contentfulclient.OperationUpdate}
+	if !*noPublish {
+		operations = append(operations, contentfulclient.OperationPublish)
+	}
+	err = contentfulclient.Preflight(ctx, cma, targetSpace, targetEnvironment, operations...)
+	if err != nil {
+		return err
+	}
+	c := &copier{
+		ctx:           ctx,
+		cma:           cma,
+		targetSpace:   targetSpace,
+		existing:      *existing,
+		publish:       !*noPublish,
+		targets:       map[model.ReferenceSysAttributes]*target{},
+		failedSources: map[model.ReferenceSysAttributes]bool{},
+		references:    map[string][]model.ReferenceSysAttributes{},
+		publishedIDs:  map[string]bool{},
+	}
+	cma.Environment = sourceEnvironment
+	err = c.collect(sourceSpace, flagSet.Args()[2:])
+	if err != nil {
+		return err
+	}
+	log.Printf("Found %d entries and %d assets in the reference graph", len(c.entries), len(c.assets))
+	cma.Environment = targetEnvironment
+	err = c.checkContentTypes()
+	if err != nil {
+		return err
+	}
+	err = c.resolveTargets()
+	if err != nil {
+		return err
+	}
+	c.copyAssets()
+	copied := c.copyEntries()
+	if c.publish {
+		c.publishEntries(copied)
+	}
+	for _, id := range flagSet.Args()[2:] {
+		t := c.targets[entryReference(id)]
+		if t != nil {
+			log.Printf("Copy of %s: https://app.contentful.com/spaces/%s/environments/%s/entries/%s",
+				id, targetSpace, targetEnvironment, t.id)
+		}
+	}
+	if c.failed > 0 {
+		return fmt.Errorf("%d entries and assets could not be copied", c.failed)
+	}
+	return nil
+}
+
+func entryReference(id string) model.ReferenceSysAttributes {
+	return model.ReferenceSysAttributes{ID: id, Type: "Link", LinkType: "Entry"}
+}
+
+func assetReference(id string) model.ReferenceSysAttributes {
+	return model.ReferenceSysAttributes{ID: id, Type: "Link", LinkType: "Asset"}
+}
+
+// fail drops the target of an entity that could not be copied, so no links are changed to point to it
+func (c *copier) fail(reference model.ReferenceSysAttributes) {
+	delete(c.targets, reference)
+	c.failedSources[reference] = true
+	c.failed++
+}
+
+// failedReference returns the first of the references that could not be copied
+func (c *copier) failedReference(references []model.ReferenceSysAttributes) (model.ReferenceSysAttributes, bool) {
+	for _, reference := range references {
+		if c.failedSources[reference] {
+			return reference, true
+		}
+	}
+	return model.ReferenceSysAttributes{}, false
+}
+
+// collect loads the root entries and everything they link to, directly or indirectly. Links to entities
+// that do not exist in the source are kept as they are.
+func (c *copier) collect(spaceID string, entryIDs []string) error {
+	seen := map[model.ReferenceSysAttributes]bool{}
+	var queue []model.ReferenceSysAttributes
+	for _, id := range entryIDs {
+		queue = append(queue, entryReference(id))
+	}
+	for len(queue) > 0 {
+		reference := queue[0]
+		queue = queue[1:]
+		if seen[reference] {
+			continue
+		}
+		seen[reference] = true
+		if reference.LinkType == "Asset" {
+			asset, err := c.cma.Assets.Get(spaceID, reference.ID)
+			if err != nil {
+				log.Printf("Asset %s could not be loaded, the link to it is kept: %v", reference.ID, err)
+				continue
+			}
+			c.assets = append(c.assets, asset)
+			continue
+		}
+		entry, err := c.cma.Entries.Get(spaceID, reference.ID)
+		if err != nil {
+			return fmt.Errorf("could not get entry %s: %v", reference.ID, err)
+		}
+		if entry == nil {
+			log.Printf("Entry %s does not exist, the link to it is kept", reference.ID)
+			continue
+		}
+		c.entries = append(c.entries, entry)
+		queue = append(queue, common.GetOutboundReferences(entry)...)
+	}
+	return nil
+}
+
+func (c *copier) checkContentTypes() error {
+	col, err := contentfulclient.GetAll(func() *contentful.Collection {
+		return c.cma.ContentTypes.List(c.targetSpace)
+	})
+	if err != nil {
+		return fmt.Errorf("could not get the content types of the target: %v", err)
+	}
+	contentTypes, err := contentfulclient.DecodeItems[model.ContentType](col)
+	if err != nil {
+		return err
+	}
+	available := make(map[string]bool, len(contentTypes))
+	for _, contentType := range contentTypes {
+		available[contentType.Sys.ID] = true
+	}
+	missing := map[string]bool{}
+	for _, entry := range c.entries {
+		if !available[entry.Sys.ContentType.Sys.ID] {
+			missing[entry.Sys.ContentType.Sys.ID] = true
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	missingIDs := make([]string, 0, len(missing))
+	for id := range missing {
+		missingIDs = append(missingIDs, id)
+	}
+	sort.Strings(missingIDs)
+	return fmt.Errorf("the target has no content types %s, copy the content model first with modeldiff -apply",
+		strings.Join(missingIDs, ", "))
+}
+
+// resolveTargets keeps the source IDs where they are free in the target and handles the others as
+// configured with existing. Existing assets are always linked and never updated.
+func (c *copier) resolveTargets() error {
+	resolve := func(reference model.ReferenceSysAttributes, collection string) error {
+		var current struct {
+			Sys struct {
+				Version int `json:"version"`
+			} `json:"sys"`
+		}
+		err := contentfulclient.Do(c.ctx, c.cma, http.MethodGet,
+			fmt.Sprintf("/spaces/%s/environments/%s/%s/%s", c.targetSpace, c.cma.Environment, collection, reference.ID), nil, &current)
+		var apiError contentfulclient.APIError
+		switch {
+		case errors.As(err, &apiError) && apiError.StatusCode == http.StatusNotFound:
+			c.targets[reference] = &target{id: reference.ID, copy: true}
+		case err != nil:
+			return fmt.Errorf("could not check if %s %s exists in the target: %v", reference.LinkType, reference.ID, err)
+		case c.existing == ExistingNewID:
+			c.targets[reference] = &target{id: newID(), copy: true}
+		case c.existing == ExistingUpdate && reference.LinkType == "Entry":
+			c.targets[reference] = &target{id: reference.ID, version: current.Sys.Version, copy: true}
+		default:
+			c.targets[reference] = &target{id: reference.ID}
+		}
+		return nil
+	}
+	for _, asset := range c.assets {
+		err := resolve(assetReference(asset.Sys.ID), "assets")
+		if err != nil {
+			return err
+		}
+	}
+	for _, entry := range c.entries {
+		err := resolve(entryReference(entry.Sys.ID), "entries")
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// copyAssets lets Contentful fetch the files from the source, which keeps working as long as the source
+// assets exist
+func (c *copier) copyAssets() {
+	for _, asset := range c.assets {
+		reference := assetReference(asset.Sys.ID)
+		t := c.targets[reference]
+		if !t.copy {
+			log.Printf("Asset %s exists in the target and is linked", asset.Sys.ID)
+			continue
+		}
+		files := map[string]common.AssetFile{}
+		if asset.Fields == nil {
+			asset.Fields = &contentful.FileFields{}
+		}
+		for locale, file := range asset.Fields.File {
+			if file == nil || file.URL == "" {
+				continue
+			}
+			fileURL := file.URL
+			if strings.HasPrefix(fileURL, "//") {
+				fileURL = "https:" + fileURL
+			}
+			files[locale] = common.AssetFile{
+				Title:       asset.Fields.Title[locale],
+				FileName:    file.Name,
+				ContentType: file.ContentType,
+				URL:         fileURL,
+			}
+		}
+		if len(files) == 0 {
+			log.Printf("Asset %s has no files and is not copied", asset.Sys.ID)
+			c.fail(reference)
+			continue
+		}
+		publish := c.publish && asset.Sys.PublishedVersion > 0
+		_, err := common.CreateAsset(c.ctx, c.cma, c.targetSpace, t.id, files, publish, assetProcessingTimeout)
+		if err != nil {
+			log.Printf("Asset %s could not be copied: %v", asset.Sys.ID, err)
+			c.fail(reference)
+			continue
+		}
+		log.Printf("Asset %s was copied to %s", asset.Sys.ID, t.id)
+	}
+}
+
+// copyEntries creates or updates the copies with all links pointing to the target IDs, leaves first.
+// Entries linking to something that could not be copied are skipped. Links to entries that are not copied
+// yet are fine for drafts, which is why publishing happens in a second step.
+func (c *copier) copyEntries() []*contentful.Entry {
+	var copied []*contentful.Entry
+	for _, entry := range common.SortByDependencies(c.entries) {
+		source := entryReference(entry.Sys.ID)
+		t := c.targets[source]
+		if !t.copy {
+			log.Printf("Entry %s exists in the target and is linked", entry.Sys.ID)
+			continue
+		}
+		references := common.GetOutboundReferences(entry)
+		if failedReference, ok := c.failedReference(references); ok {
+			log.Printf("Entry %s is not copied, it links to %s %s which could not be copied",
+				entry.Sys.ID, strings.ToLower(failedReference.LinkType), failedReference.ID)
+			c.fail(source)
+			continue
+		}
+		for reference, referenceTarget := range c.targets {
+			if referenceTarget.id != reference.ID {
+				common.RelinkReferences(entry, reference, referenceTarget.id, "")
+			}
+		}
+		entryCopy := &contentful.Entry{
+			Fields: entry.Fields,
+			Sys: &contentful.Sys{
+				ID:      t.id,
+				Version: t.version,
+				ContentType: &contentful.ContentType{
+					Sys: &contentful.Sys{
+						ID:       entry.Sys.ContentType.Sys.ID,
+						Type:     "Link",
+						LinkType: "ContentType",
+					},
+				},
+			},
+		}
+		err := c.cma.Entries.Upsert(c.targetSpace, entryCopy)
+		if err != nil {
+			log.Printf("Entry %s could not be copied: %v", entry.Sys.ID, err)
+			c.fail(source)
+			continue
+		}
+		log.Printf("Entry %s was copied to %s", entry.Sys.ID, t.id)
+		c.references[t.id] = references
+		if entry.Sys.PublishedVersion > 0 {
+			c.publishedIDs[t.id] = true
+		}
+		copied = append(copied, entryCopy)
+	}
+	return copied
+}
+
+// publishEntries publishes the copies of published entries, leaves first. Within cycles an entry may have
+// been copied before one it links to failed, such copies stay in draft.
+func (c *copier) publishEntries(copied []*contentful.Entry) {
+	for _, entry := range common.SortByDependencies(copied) {
+		if !c.publishedIDs[entry.Sys.ID] {
+			continue
+		}
+		if failedReference, ok := c.failedReference(c.references[entry.Sys.ID]); ok {
+			log.Printf("Entry %s is not published, it links to %s %s which could not be copied",
+				entry.Sys.ID, strings.ToLower(failedReference.LinkType), failedReference.ID)
+			c.failed++
+			continue
+		}
+		err := c.cma.Entries.Publish(c.targetSpace, entry)
+		if err != nil {
+			log.Printf("Entry %s could not be published: %v", entry.Sys.ID, err)
+			c.failed++
+		}
+	}
+}
+
+// newID returns a random ID in the length Contentful uses for generated IDs
+func newID() string {
+	byt := make([]byte, 11)
+	_, _ = rand.Read(byt)
+	return hex.EncodeToString(byt)
+}
@@ -0,0 +1,276 @@
+package docgen
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"html/template"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/foomo/contentful"
+
+	"github.com/foomo/contentfulcommander/cmd/common"
+	"github.com/foomo/contentfulcommander/contentfulclient"
+	"github.com/foomo/contentfulcommander/model"
+)
+
+const (
+	FormatMarkdown = "markdown"
+	FormatHTML     = "html"
+)
+
+type contentTypeDoc struct {
+	ID          string
+	Name        string
+	Description string
+	Fields      []fieldDoc
+}
+
+type fieldDoc struct {
+	ID          string
+	Name        string
+	Type        string
+	Required    bool
+	Localized   bool
+	Disabled    bool
+	Omitted     bool
+	Validations []string
+}
+
+// relationship is a reference field of a content type, To is empty if any content type can be linked
+type relationship struct {
+	From  string
+	Field string
+	To    string
+}
+
+type modelDoc struct {
+	Title         string
+	ContentTypes  []contentTypeDoc
+	Relationships []relationship
+}
+
+func Run(cma *contentful.Contentful, params []string) error {
+	flagSet := flag.NewFlagSet("docgen", flag.ContinueOnError)
+	format := flagSet.String("format", FormatMarkdown, "output format, markdown or html")
+	out := flagSet.String("out", "", "file to write to instead of stdout")
+	err := flagSet.Parse(params)
+	if err != nil {
+		return err
+	}
+	if flagSet.NArg() != 1 {
+		return errors.New("docgen needs exactly one space parameter")
+	}
+	if *format != FormatMarkdown && *format != FormatHTML {
+		return fmt.Errorf("unknown format %s, use %s or %s", *format, FormatMarkdown, FormatHTML)
+	}
+	spaceID, environment := contentfulclient.GetSpaceAndEnvironment(flagSet.Arg(0))
+	cma.Environment = environment
+	err = contentfulclient.Preflight(context.Background(), cma, spaceID, environment, contentfulclient.OperationRead)
+	if err != nil {
+		return err
+	}
+	col, err := contentfulclient.GetAll(func() *contentful.Collection {
+		return cma.ContentTypes.List(spaceID)
+	})
+	if err != nil {
+		return fmt.Errorf("could not get content types: %v", err)
+	}
+	contentTypes, err := contentfulclient.DecodeItems[model.ContentType](col)
+	if err != nil {
+		return err
+	}
+	doc := getModelDoc(fmt.Sprintf("%s/%s", spaceID, environment), contentTypes)
+
+	var writer io.Writer = os.Stdout
+	if *out != "" {
+		file, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		writer = file
+	}
+	if *format == FormatHTML {
+		err = htmlTemplate.Execute(writer, doc)
+	} else {
+		err = writeMarkdown(writer, doc)
+	}
+	if err != nil {
+		return fmt.Errorf("could not write the documentation: %v", err)
+	}
+	if *out != "" {
+		log.Printf("Documented %d content types in %s", len(doc.ContentTypes), *out)
+	}
+	return nil
+}
+
+// getModelDoc sorts content types and relationships by ID, so that the generated documentation only
+// changes when the content model does
+func getModelDoc(title string, contentTypes []model.ContentType) modelDoc {
+	sort.Slice(contentTypes, func(i, j int) bool {
+		return contentTypes[i].Sys.ID < contentTypes[j].Sys.ID
+	})
+	doc := modelDoc{Title: title}
+	for _, contentType := range contentTypes {
+		contentTypeDoc := contentTypeDoc{
+			ID:          contentType.Sys.ID,
+			Name:        contentType.Name,
+			Description: contentType.Description,
+		}
+		for _, field := range contentType.Fields {
+			contentTypeDoc.Fields = append(contentTypeDoc.Fields, getFieldDoc(field))
+			allowed, ok := common.GetEntryLinkValidation(field)
+			if !ok {
+				continue
+			}
+			if len(allowed) == 0 {
+				allowed = []string{""}
+			}
+			for _, to := range allowed {
+				doc.Relationships = append(doc.Relationships, relationship{From: contentType.Sys.ID, Field: field.ID, To: to})
+			}
+		}
+		doc.ContentTypes = append(doc.ContentTypes, contentTypeDoc)
+	}
+	return doc
+}
+
+func getFieldDoc(field model.ContentTypeField) fieldDoc {
+	doc := fieldDoc{
+		ID:        field.ID,
+		Name:      field.Name,
+		Type:      getFieldType(field),
+		Required:  field.Required,
+		Localized: field.Localized,
+		Disabled:  field.Disabled,
+		Omitted:   field.Omitted,
+	}
+	for _, validation := range field.Validations {
+		doc.Validations = append(doc.Validations, getJSON(validation))
+	}
+	if field.Items != nil {
+		for _, validation := range field.Items.Validations {
+			doc.Validations = append(doc.Validations, "items: "+getJSON(validation))
+		}
+	}
+	return doc
+}
+
+func getFieldType(field model.ContentTypeField) string {
+	switch {
+	case field.Type == "Link":
+		return "Link to " + field.LinkType
+	case field.Type == "Array" && field.Items != nil && field.Items.Type == "Link":
+		return "Array of Link to " + field.Items.LinkType
+	case field.Type == "Array" && field.Items != nil:
+		return "Array of " + field.Items.Type
+	}
+	return field.Type
+}
+
+func getJSON(value any) string {
+	bytes, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(bytes)
+}
+
+func writeMarkdown(writer io.Writer, doc modelDoc) error {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# Content model of %s\n\n", doc.Title)
+	if len(doc.Relationships) > 0 {
+		sb.WriteString("## Relationships\n\n```mermaid\nflowchart LR\n")
+		for _, rel := range doc.Relationships {
+			to := rel.To
+			if to == "" {
+				to = "any[any content type]"
+			}
+			fmt.Fprintf(&sb, "    %s -->|%s| %s\n", rel.From, rel.Field, to)
+		}
+		sb.WriteString("```\n\n")
+	}
+	sb.WriteString("## Content types\n\n")
+	for _, contentType := range doc.ContentTypes {
+		fmt.Fprintf(&sb, "### %s (`%s`)\n\n", contentType.Name, contentType.ID)
+		if contentType.Description != "" {
+			sb.WriteString(contentType.Description + "\n\n")
+		}
+		sb.WriteString("| Field | ID | Type | Required | Localized | Validations |\n")
+		sb.WriteString("|-------|----|------|----------|-----------|-------------|\n")
+		for _, field := range contentType.Fields {
+			name := field.Name
+			if field.Disabled || field.Omitted {
+				name += " _(hidden)_"
+			}
+			validations := make([]string, 0, len(field.Validations))
+			for _, validation := range field.Validations {
+				validations = append(validations, "`"+validation+"`")
+			}
+			fmt.Fprintf(&sb, "| %s | `%s` | %s | %s | %s | %s |\n",
+				escapeMarkdownCell(name), field.ID, field.Type, yesNo(field.Required), yesNo(field.Localized),
+				escapeMarkdownCell(strings.Join(validations, "<br>")))
+		}
+		sb.WriteString("\n")
+	}
+	_, err := io.WriteString(writer, sb.String())
+	return err
+}
+
+func escapeMarkdownCell(value string) string {
+	return strings.ReplaceAll(value, "|", `\|`)
+}
+
+func yesNo(value bool) string {
+	if value {
+		return "yes"
+	}
+	return "no"
+}
+
+var htmlTemplate = template.Must(template.New("docgen").Funcs(template.FuncMap{"yesNo": yesNo}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Content model of {{.Title}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; margin-bottom: 2em; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: left; vertical-align: top; }
+code { font-size: 0.9em; }
+.hidden { color: #999; }
+</style>
+</head>
+<body>
+<h1>Content model of {{.Title}}</h1>
+{{- if .Relationships}}
+<h2>Relationships</h2>
+<ul>
+{{- range .Relationships}}
+<li><a href="#{{.From}}">{{.From}}</a>.{{.Field}} &rarr; {{if .To}}<a href="#{{.To}}">{{.To}}</a>{{else}}any content type{{end}}</li>
+{{- end}}
+</ul>
+{{- end}}
+<h2>Content types</h2>
+{{- range .ContentTypes}}
+<h3 id="{{.ID}}">{{.Name}} (<code>{{.ID}}</code>)</h3>
+{{- if .Description}}
+<p>{{.Description}}</p>
+{{- end}}
+<table>
+<tr><th>Field</th><th>ID</th><th>Type</th><th>Required</th><th>Localized</th><th>Validations</th></tr>
+{{- range .Fields}}
+<tr{{if or .Disabled .Omitted}} class="hidden"{{end}}><td>{{.Name}}</td><td><code>{{.ID}}</code></td><td>{{.Type}}</td><td>{{yesNo .Required}}</td><td>{{yesNo .Localized}}</td><td>{{range $i, $v := .Validations}}{{if $i}}<br>{{end}}<code>{{$v}}</code>{{end}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+</body>
+</html>
+`))
@@ -0,0 +1,53 @@
+package download
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+
+	"github.com/foomo/contentful"
+
+	"github.com/foomo/contentfulcommander/cmd/common"
+	"github.com/foomo/contentfulcommander/contentfulclient"
+)
+
+func Run(cma *contentful.Contentful, params []string) error {
+	flagSet := flag.NewFlagSet("download", flag.ContinueOnError)
+	mimeTypeGroup := flagSet.String("mimetype", "", "only download assets of this MIME type group, e.g. image or pdfdocument")
+	parallel := flagSet.Int("parallel", 5, "number of files downloaded at the same time")
+	err := flagSet.Parse(params)
+	if err != nil {
+		return err
+	}
+	if flagSet.NArg() != 2 {
+		return errors.New("download needs a space and a target directory")
+	}
+	spaceID, environment := contentfulclient.GetSpaceAndEnvironment(flagSet.Arg(0))
+	dir := flagSet.Arg(1)
+	cma.Environment = environment
+	ctx := context.Background()
+	err = contentfulclient.Preflight(ctx, cma, spaceID, environment, contentfulclient.OperationRead)
+	if err != nil {
+		return err
+	}
+	col, err := contentfulclient.GetAll(func() *contentful.Collection {
+		collection := cma.Assets.List(spaceID)
+		if *mimeTypeGroup != "" {
+			collection.Query.MimeType(*mimeTypeGroup)
+		}
+		return collection
+	})
+	if err != nil {
+		return fmt.Errorf("could not get assets: %v", err)
+	}
+	assets, err := contentfulclient.DecodeItems[*contentful.Asset](col)
+	if err != nil {
+		return err
+	}
+	log.Printf("Downloading the files of %d assets to %s", len(assets), dir)
+	downloaded, err := common.DownloadAssets(ctx, assets, dir, *parallel)
+	log.Printf("Downloaded %d files", downloaded)
+	return err
+}
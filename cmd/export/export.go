@@ -0,0 +1,135 @@
+package export
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/foomo/contentful"
+
+	"github.com/foomo/contentfulcommander/contentfulclient"
+)
+
+const (
+	FormatJSON   = "json"
+	FormatNDJSON = "ndjson"
+)
+
+// Kinds are the item collections of an export, each one is written to a file named kind.format
+var Kinds = []string{"locales", "contentTypes", "entries", "assets"}
+
+func Run(cma *contentful.Contentful, params []string) error {
+	flagSet := flag.NewFlagSet("export", flag.ContinueOnError)
+	format := flagSet.String("format", FormatJSON, "file format, json or ndjson")
+	err := flagSet.Parse(params)
+	if err != nil {
+		return err
+	}
+	if flagSet.NArg() != 2 {
+		return errors.New("export needs a space and a target directory")
+	}
+	if *format != FormatJSON && *format != FormatNDJSON {
+		return fmt.Errorf("unknown format %s, use %s or %s", *format, FormatJSON, FormatNDJSON)
+	}
+	spaceID, environment := contentfulclient.GetSpaceAndEnvironment(flagSet.Arg(0))
+	dir := flagSet.Arg(1)
+	cma.Environment = environment
+	err = contentfulclient.Preflight(context.Background(), cma, spaceID, environment, contentfulclient.OperationRead)
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(dir, 0o755)
+	if err != nil {
+		return err
+	}
+	collections := map[string]func() *contentful.Collection{
+		"locales":      func() *contentful.Collection { return cma.Locales.List(spaceID) },
+		"contentTypes": func() *contentful.Collection { return cma.ContentTypes.List(spaceID) },
+		"entries":      func() *contentful.Collection { return cma.Entries.List(spaceID) },
+		"assets":       func() *contentful.Collection { return cma.Assets.List(spaceID) },
+	}
+	for _, kind := range Kinds {
+		// the raw items are written as they come from the API, decoding them would drop sys
+		// attributes and field validations the contentful package does not know
+		collection, err := contentfulclient.GetAll(collections[kind])
+		if err != nil {
+			return fmt.Errorf("could not load %s: %v", kind, err)
+		}
+		filename := filepath.Join(dir, kind+"."+*format)
+		err = writeItems(filename, *format, collection.Items)
+		if err != nil {
+			return fmt.Errorf("could not write %s: %v", filename, err)
+		}
+		log.Printf("Exported %d %s to %s", len(collection.Items), kind, filename)
+	}
+	return nil
+}
+
+func writeItems(filename, format string, items []any) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	writer := bufio.NewWriter(file)
+	if format == FormatJSON {
+		if items == nil {
+			items = []any{}
+		}
+		encoder := json.NewEncoder(writer)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(items)
+	} else {
+		encoder := json.NewEncoder(writer)
+		for _, item := range items {
+			err = encoder.Encode(item)
+			if err != nil {
+				break
+			}
+		}
+	}
+	if err != nil {
+		return err
+	}
+	err = writer.Flush()
+	if err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+// ReadItems reads the items of one kind from an export directory written in either format
+func ReadItems(dir, kind string) ([]json.RawMessage, error) {
+	for _, format := range []string{FormatJSON, FormatNDJSON} {
+		file, err := os.Open(filepath.Join(dir, kind+"."+format))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		var items []json.RawMessage
+		decoder := json.NewDecoder(file)
+		if format == FormatJSON {
+			err = decoder.Decode(&items)
+			return items, err
+		}
+		for decoder.More() {
+			var item json.RawMessage
+			err = decoder.Decode(&item)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		return items, nil
+	}
+	return nil, fmt.Errorf("no %s found in %s", kind, dir)
+}
@@ -0,0 +1,133 @@
+package freshness
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"sort"
+
+	"github.com/foomo/contentful"
+
+	"github.com/foomo/contentfulcommander/cmd/common"
+	"github.com/foomo/contentfulcommander/contentfulclient"
+)
+
+type fieldChange struct {
+	ChangedAt string
+	ChangedBy string
+}
+
+func Run(cma *contentful.Contentful, params []string) error {
+	flagSet := flag.NewFlagSet("freshness", flag.ContinueOnError)
+	contentTypeID := flagSet.String("contenttype", "", "analyze all entries of this content type")
+	sourceLocale := flagSet.String("source", "", "mark other locales that were changed before this one as outdated")
+	onlyOutdated := flagSet.Bool("outdated", false, "only show outdated locales, requires source")
+	err := flagSet.Parse(params)
+	if err != nil {
+		return err
+	}
+	if flagSet.NArg() < 1 || (flagSet.NArg() == 1) == (*contentTypeID == "") {
+		return errors.New("freshness needs a space and either entry IDs or a content type")
+	}
+	if *onlyOutdated && *sourceLocale == "" {
+		return errors.New("outdated requires a source locale")
+	}
+	spaceID, environment := contentfulclient.GetSpaceAndEnvironment(flagSet.Arg(0))
+	cma.Environment = environment
+	ctx := context.Background()
+	err = contentfulclient.Preflight(ctx, cma, spaceID, environment, contentfulclient.OperationRead)
+	if err != nil {
+		return err
+	}
+	entryIDs := flagSet.Args()[1:]
+	if *contentTypeID != "" {
+		entries, err := common.GetEntriesByContentType(cma, spaceID, *contentTypeID)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			entryIDs = append(entryIDs, entry.Sys.ID)
+		}
+	}
+	for _, entryID := range entryIDs {
+		snapshots, err := contentfulclient.GetEntrySnapshots(ctx, cma, spaceID, entryID)
+		if err != nil {
+			return fmt.Errorf("could not get snapshots of entry %s: %v", entryID, err)
+		}
+		if len(snapshots) == 0 {
+			fmt.Printf("Entry %s has never been published\n", entryID)
+			continue
+		}
+		printFreshness(entryID, getLastChanges(snapshots), *sourceLocale, *onlyOutdated)
+	}
+	return nil
+}
+
+// getLastChanges returns per field and locale the snapshot in which the value was last changed
+func getLastChanges(snapshots []contentfulclient.Snapshot) map[string]map[string]fieldChange {
+	lastChanges := map[string]map[string]fieldChange{}
+	previousValues := map[string]map[string]string{}
+	for _, snapshot := range snapshots {
+		if snapshot.Snapshot == nil {
+			continue
+		}
+		change := fieldChange{ChangedAt: snapshot.Sys.CreatedAt, ChangedBy: "unknown"}
+		if snapshot.Sys.CreatedBy != nil {
+			change.ChangedBy = snapshot.Sys.CreatedBy.Sys.ID
+		}
+		for fieldID, field := range snapshot.Snapshot.Fields {
+			localizedValue, ok := field.(map[string]any)
+			if !ok {
+				continue
+			}
+			if _, ok := lastChanges[fieldID]; !ok {
+				lastChanges[fieldID] = map[string]fieldChange{}
+				previousValues[fieldID] = map[string]string{}
+			}
+			for locale, value := range localizedValue {
+				byt, _ := json.Marshal(value)
+				if previous, ok := previousValues[fieldID][locale]; ok && previous == string(byt) {
+					continue
+				}
+				previousValues[fieldID][locale] = string(byt)
+				lastChanges[fieldID][locale] = change
+			}
+		}
+	}
+	return lastChanges
+}
+
+func printFreshness(entryID string, lastChanges map[string]map[string]fieldChange, sourceLocale string, onlyOutdated bool) {
+	fieldIDs := make([]string, 0, len(lastChanges))
+	for fieldID := range lastChanges {
+		fieldIDs = append(fieldIDs, fieldID)
+	}
+	sort.Strings(fieldIDs)
+	headerPrinted := false
+	for _, fieldID := range fieldIDs {
+		locales := make([]string, 0, len(lastChanges[fieldID]))
+		for locale := range lastChanges[fieldID] {
+			locales = append(locales, locale)
+		}
+		sort.Strings(locales)
+		source, hasSource := lastChanges[fieldID][sourceLocale]
+		for _, locale := range locales {
+			change := lastChanges[fieldID][locale]
+			outdated := hasSource && locale != sourceLocale && change.ChangedAt < source.ChangedAt
+			if onlyOutdated && !outdated {
+				continue
+			}
+			if !headerPrinted {
+				fmt.Printf("Entry %s\n", entryID)
+				headerPrinted = true
+			}
+			fmt.Printf("    %-24s %-8s %s by %s", fieldID, locale, change.ChangedAt, change.ChangedBy)
+			if outdated {
+				fmt.Printf(" OUTDATED, %s changed %s", sourceLocale, source.ChangedAt)
+			}
+			fmt.Println()
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package getentry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+
+	"github.com/foomo/contentful"
+
+	"github.com/foomo/contentfulcommander/contentfulclient"
+)
+
+func Run(cma *contentful.Contentful, params []string) error {
+	flagSet := flag.NewFlagSet("getentry", flag.ContinueOnError)
+	out := flagSet.String("out", "", "file to write to instead of stdout")
+	err := flagSet.Parse(params)
+	if err != nil {
+		return err
+	}
+	if flagSet.NArg() != 2 {
+		return errors.New("getentry needs a space and an entry ID")
+	}
+	spaceID, environment := contentfulclient.GetSpaceAndEnvironment(flagSet.Arg(0))
+	entryID := flagSet.Arg(1)
+	ctx := context.Background()
+	err = contentfulclient.Preflight(ctx, cma, spaceID, environment, contentfulclient.OperationRead)
+	if err != nil {
+		return err
+	}
+	// the entry is written exactly as the API returns it, decoding it would drop metadata and sys attributes
+	var entry json.RawMessage
+	err = contentfulclient.Do(ctx, cma, http.MethodGet,
+		fmt.Sprintf("/spaces/%s/environments/%s/entries/%s", spaceID, environment, entryID), nil, &entry)
+	if err != nil {
+		return fmt.Errorf("could not get entry %s: %v", entryID, err)
+	}
+	var buf bytes.Buffer
+	err = json.Indent(&buf, entry, "", "  ")
+	if err != nil {
+		return err
+	}
+	buf.WriteString("\n")
+	if *out == "" {
+		_, err = os.Stdout.Write(buf.Bytes())
+		return err
+	}
+	err = os.WriteFile(*out, buf.Bytes(), 0o644)
+	if err != nil {
+		return err
+	}
+	log.Printf("Entry %s was written to %s", entryID, *out)
+	return nil
+}
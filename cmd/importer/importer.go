@@ -0,0 +1,319 @@
+package importer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/foomo/contentful"
+
+	"github.com/foomo/contentfulcommander/cmd/common"
+	"github.com/foomo/contentfulcommander/cmd/export"
+	"github.com/foomo/contentfulcommander/contentfulclient"
+)
+
+const assetProcessingTimeout = 5 * time.Minute
+
+type sys struct {
+	ID               string `json:"id"`
+	Type             string `json:"type"`
+	Version          int    `json:"version"`
+	PublishedVersion int    `json:"publishedVersion,omitempty"`
+	ArchivedVersion  int    `json:"archivedVersion,omitempty"`
+	ContentType      *struct {
+		Sys struct {
+			ID string `json:"id"`
+		} `json:"sys"`
+	} `json:"contentType,omitempty"`
+}
+
+type item struct {
+	Sys    sys                                   `json:"sys"`
+	Fields map[string]map[string]json.RawMessage `json:"fields"`
+}
+
+// importer keeps the state of one import run, the exported and imported sys of every item are needed
+// to restore the publishing status once all items exist
+type importer struct {
+	ctx      context.Context
+	cma      *contentful.Contentful
+	spaceID  string
+	failed   int
+	imported []imported
+}
+
+type imported struct {
+	path     string
+	exported sys
+	target   sys
+	fields   map[string]map[string]json.RawMessage
+}
+
+func Run(cma *contentful.Contentful, params []string) error {
+	flagSet := flag.NewFlagSet("import", flag.ContinueOnError)
+	noPublish := flagSet.Bool("nopublish", false, "do not restore the publishing and archiving status")
+	err := flagSet.Parse(params)
+	if err != nil {
+		return err
+	}
+	if flagSet.NArg() != 2 {
+		return errors.New("import needs an export directory and a space")
+	}
+	dir := flagSet.Arg(0)
+	spaceID, environment := contentfulclient.GetSpaceAndEnvironment(flagSet.Arg(1))
+	cma.Environment = environment
+	operations := []contentfulclient.Operation{contentfulclient.OperationUpdate}
+	if !*noPublish {
+		operations = append(operations, contentfulclient.OperationPublish, contentfulclient.OperationArchive)
+	}
+	err = contentfulclient.Preflight(context.Background(), cma, spaceID, environment, operations...)
+	if err != nil {
+		return err
+	}
+	assets, err := readItems(dir, "assets")
+	if err != nil {
+		return err
+	}
+	entries, err := readItems(dir, "entries")
+	if err != nil {
+		return err
+	}
+	imp := &importer{ctx: context.Background(), cma: cma, spaceID: spaceID}
+
+	// assets come first so that they are processed while the entries are imported
+	var uploadedAssetIDs []string
+	for _, asset := range assets {
+		uploaded, err := imp.importAsset(asset)
+		if err != nil {
+			log.Printf("Asset %s could not be imported: %v", asset.Sys.ID, err)
+			imp.failed++
+			continue
+		}
+		if uploaded {
+			uploadedAssetIDs = append(uploadedAssetIDs, asset.Sys.ID)
+		}
+	}
+	log.Printf("Imported %d assets", len(assets))
+	for _, entry := range entries {
+		err := imp.importEntry(entry)
+		if err != nil {
+			log.Printf("Entry %s could not be imported: %v", entry.Sys.ID, err)
+			imp.failed++
+		}
+	}
+	log.Printf("Imported %d entries", len(entries))
+
+	if len(uploadedAssetIDs) > 0 {
+		log.Printf("Waiting for %d new assets to be processed", len(uploadedAssetIDs))
+		err = common.WaitForAssetsProcessed(imp.ctx, cma, spaceID, uploadedAssetIDs, assetProcessingTimeout, 5)
+		if err != nil {
+			log.Print(err)
+		}
+	}
+	if !*noPublish {
+		imp.restoreStatus()
+	}
+	total := len(assets) + len(entries)
+	if imp.failed > 0 {
+		return fmt.Errorf("%d of %d items could not be fully imported", imp.failed, total)
+	}
+	log.Printf("Imported %d items from %s into %s/%s", total, dir, spaceID, environment)
+	return nil
+}
+
+func readItems(dir, kind string) ([]item, error) {
+	raw, err := export.ReadItems(dir, kind)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]item, 0, len(raw))
+	for _, rawItem := range raw {
+		var it item
+		err := json.Unmarshal(rawItem, &it)
+		if err != nil {
+			return nil, fmt.Errorf("could not decode %s in %s: %v", kind, dir, err)
+		}
+		items = append(items, it)
+	}
+	return items, nil
+}
+
+func (imp *importer) path(collection, id string) string {
+	return fmt.Sprintf("/spaces/%s/environments/%s/%s/%s", imp.spaceID, imp.cma.Environment, collection, id)
+}
+
+// getTarget returns the sys of the item in the target environment, which is nil if it does not exist yet
+func (imp *importer) getTarget(path string) (*sys, error) {
+	var target item
+	err := contentfulclient.Do(imp.ctx, imp.cma, http.MethodGet, path, nil, &target)
+	var apiError contentfulclient.APIError
+	if errors.As(err, &apiError) && apiError.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	// archived items cannot be changed
+	if target.Sys.ArchivedVersion > 0 {
+		err = imp.do(http.MethodDelete, path+"/archived", target.Sys.Version, nil, &target)
+		if err != nil {
+			return nil, fmt.Errorf("could not unarchive: %v", err)
+		}
+	}
+	return &target.Sys, nil
+}
+
+// put creates or updates the item with the same ID as in the export
+func (imp *importer) put(path string, target *sys, headers map[string]string, it item) (sys, error) {
+	if target != nil {
+		headers["X-Contentful-Version"] = strconv.Itoa(target.Version)
+	}
+	var updated item
+	err := contentfulclient.DoWithHeaders(imp.ctx, imp.cma, http.MethodPut, path, headers,
+		map[string]any{"fields": it.Fields}, &updated)
+	if err != nil {
+		return sys{}, err
+	}
+	imp.imported = append(imp.imported, imported{path: path, exported: it.Sys, target: updated.Sys, fields: it.Fields})
+	return updated.Sys, nil
+}
+
+func (imp *importer) importEntry(entry item) error {
+	if entry.Sys.ContentType == nil {
+		return errors.New("entry has no content type")
+	}
+	path := imp.path("entries", entry.Sys.ID)
+	target, err := imp.getTarget(path)
+	if err != nil {
+		return err
+	}
+	_, err = imp.put(path, target, map[string]string{"X-Contentful-Content-Type": entry.Sys.ContentType.Sys.ID}, entry)
+	return err
+}
+
+// importAsset updates existing assets with the exported fields. New assets get their files uploaded from
+// the exported URLs, which only works while the assets of the source space still exist.
+func (imp *importer) importAsset(asset item) (bool, error) {
+	path := imp.path("assets", asset.Sys.ID)
+	target, err := imp.getTarget(path)
+	if err != nil {
+		return false, err
+	}
+	if target != nil {
+		_, err = imp.put(path, target, map[string]string{}, asset)
+		return false, err
+	}
+	var locales []string
+	for locale, rawFile := range asset.Fields["file"] {
+		var file map[string]any
+		err := json.Unmarshal(rawFile, &file)
+		if err != nil {
+			return false, err
+		}
+		if url, ok := file["url"].(string); ok {
+			if strings.HasPrefix(url, "//") {
+				url = "https:" + url
+			}
+			file = map[string]any{"upload": url, "fileName": file["fileName"], "contentType": file["contentType"]}
+			locales = append(locales, locale)
+		}
+		rawFile, err = json.Marshal(file)
+		if err != nil {
+			return false, err
+		}
+		asset.Fields["file"][locale] = rawFile
+	}
+	created, err := imp.put(path, nil, map[string]string{}, asset)
+	if err != nil {
+		return false, err
+	}
+	for _, locale := range locales {
+		err = imp.do(http.MethodPut, fmt.Sprintf("%s/files/%s/process", path, locale), created.Version, nil, nil)
+		if err != nil {
+			return false, fmt.Errorf("could not process file for locale %s: %v", locale, err)
+		}
+	}
+	return len(locales) > 0, nil
+}
+
+// restoreStatus publishes, unpublishes and archives the imported items like they were in the export. Only the
+// latest version of each item is exported, so items with unpublished changes get these changes published.
+func (imp *importer) restoreStatus() {
+	for _, it := range imp.publishOrder() {
+		var err error
+		switch {
+		case it.exported.ArchivedVersion > 0:
+			// published items have to be unpublished before they can be archived
+			if it.target.PublishedVersion > 0 {
+				err = imp.setStatus(it.path, "published", http.MethodDelete)
+			}
+			if err == nil {
+				err = imp.setStatus(it.path, "archived", http.MethodPut)
+			}
+		case it.exported.PublishedVersion > 0:
+			if it.exported.Version-it.exported.PublishedVersion > 1 {
+				log.Printf("%s %s had unpublished changes, which are published now", it.exported.Type, it.exported.ID)
+			}
+			err = imp.setStatus(it.path, "published", http.MethodPut)
+		case it.target.PublishedVersion > 0:
+			err = imp.setStatus(it.path, "published", http.MethodDelete)
+		}
+		if err != nil {
+			log.Printf("Could not restore the status of %s %s: %v", it.exported.Type, it.exported.ID, err)
+			imp.failed++
+		}
+	}
+}
+
+// publishOrder returns the imported assets first and then the entries, each after the entries it links to,
+// so that published entries never link to entries that are not published yet
+func (imp *importer) publishOrder() []imported {
+	var ordered []imported
+	var entries []*contentful.Entry
+	entryMap := map[string]imported{}
+	for _, it := range imp.imported {
+		if it.exported.Type != "Entry" {
+			ordered = append(ordered, it)
+			continue
+		}
+		entry := &contentful.Entry{Sys: &contentful.Sys{ID: it.exported.ID}, Fields: map[string]any{}}
+		for fieldID, localized := range it.fields {
+			values := map[string]any{}
+			for locale, raw := range localized {
+				var value any
+				if json.Unmarshal(raw, &value) == nil {
+					values[locale] = value
+				}
+			}
+			entry.Fields[fieldID] = values
+		}
+		entries = append(entries, entry)
+		entryMap[it.exported.ID] = it
+	}
+	for _, entry := range common.SortByDependencies(entries) {
+		ordered = append(ordered, entryMap[entry.Sys.ID])
+	}
+	return ordered
+}
+
+// setStatus fetches the current version first because processing assets creates new versions
+func (imp *importer) setStatus(path, status, method string) error {
+	var current item
+	err := contentfulclient.Do(imp.ctx, imp.cma, http.MethodGet, path, nil, &current)
+	if err != nil {
+		return err
+	}
+	return imp.do(method, path+"/"+status, current.Sys.Version, nil, nil)
+}
+
+func (imp *importer) do(method, path string, version int, body, v any) error {
+	return contentfulclient.DoWithHeaders(imp.ctx, imp.cma, method, path,
+		map[string]string{"X-Contentful-Version": strconv.Itoa(version)}, body, v)
+}
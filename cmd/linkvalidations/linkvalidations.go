@@ -0,0 +1,163 @@
+package linkvalidations
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/foomo/contentful"
+
+	"github.com/foomo/contentfulcommander/cmd/common"
+	"github.com/foomo/contentfulcommander/contentfulclient"
+	"github.com/foomo/contentfulcommander/model"
+)
+
+// usage counts the linked content types of one reference field
+type usage struct {
+	allowed []string
+	linked  map[string]int
+	broken  int
+}
+
+func Run(cma *contentful.Contentful, params []string) error {
+	flagSet := flag.NewFlagSet("linkvalidations", flag.ContinueOnError)
+	contentTypeID := flagSet.String("contenttype", "", "only look at reference fields of this content type")
+	err := flagSet.Parse(params)
+	if err != nil {
+		return err
+	}
+	if flagSet.NArg() != 1 {
+		return errors.New("linkvalidations needs exactly one space parameter")
+	}
+	spaceID, environment := contentfulclient.GetSpaceAndEnvironment(flagSet.Arg(0))
+	cma.Environment = environment
+	err = contentfulclient.Preflight(context.Background(), cma, spaceID, environment, contentfulclient.OperationRead)
+	if err != nil {
+		return err
+	}
+	col, err := contentfulclient.GetAll(func() *contentful.Collection {
+		return cma.ContentTypes.List(spaceID)
+	})
+	if err != nil {
+		return fmt.Errorf("could not get content types: %v", err)
+	}
+	contentTypes, err := contentfulclient.DecodeItems[model.ContentType](col)
+	if err != nil {
+		return err
+	}
+	// all entries are needed to look up the content types of linked entries
+	entries, err := common.GetAllEntries(cma, spaceID)
+	if err != nil {
+		return err
+	}
+	log.Printf("Loaded %d content types and %d entries", len(contentTypes), len(entries))
+	entryContentTypes := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		entryContentTypes[entry.Sys.ID] = entry.Sys.ContentType.Sys.ID
+	}
+
+	usages := map[string]map[string]*usage{}
+	for _, contentType := range contentTypes {
+		if *contentTypeID != "" && contentType.Sys.ID != *contentTypeID {
+			continue
+		}
+		fields := map[string]*usage{}
+		for _, field := range contentType.Fields {
+			if allowed, ok := common.GetEntryLinkValidation(field); ok {
+				fields[field.ID] = &usage{allowed: allowed, linked: map[string]int{}}
+			}
+		}
+		if len(fields) > 0 {
+			usages[contentType.Sys.ID] = fields
+		}
+	}
+	for _, entry := range entries {
+		fields, ok := usages[entry.Sys.ContentType.Sys.ID]
+		if !ok {
+			continue
+		}
+		common.WalkReferences(entry, func(fieldID, _ string, reference model.ReferenceSysAttributes) {
+			fieldUsage, ok := fields[fieldID]
+			if !ok || reference.LinkType != "Entry" {
+				return
+			}
+			linkedContentType, ok := entryContentTypes[reference.ID]
+			if !ok {
+				fieldUsage.broken++
+				return
+			}
+			fieldUsage.linked[linkedContentType]++
+		})
+	}
+	printReport(usages)
+	return nil
+}
+
+func printReport(usages map[string]map[string]*usage) {
+	contentTypeIDs := make([]string, 0, len(usages))
+	for contentTypeID := range usages {
+		contentTypeIDs = append(contentTypeIDs, contentTypeID)
+	}
+	sort.Strings(contentTypeIDs)
+	proposals := 0
+	for _, contentTypeID := range contentTypeIDs {
+		fieldIDs := make([]string, 0, len(usages[contentTypeID]))
+		for fieldID := range usages[contentTypeID] {
+			fieldIDs = append(fieldIDs, fieldID)
+		}
+		sort.Strings(fieldIDs)
+		for _, fieldID := range fieldIDs {
+			fieldUsage := usages[contentTypeID][fieldID]
+			linked := make([]string, 0, len(fieldUsage.linked))
+			for linkedContentType := range fieldUsage.linked {
+				linked = append(linked, linkedContentType)
+			}
+			sort.Strings(linked)
+			allowed := "any"
+			if len(fieldUsage.allowed) > 0 {
+				allowed = strings.Join(fieldUsage.allowed, ",")
+			}
+			fmt.Printf("%s.%s\n", contentTypeID, fieldID)
+			fmt.Printf("    allowed:  %s\n", allowed)
+			for _, linkedContentType := range linked {
+				fmt.Printf("    linked:   %s (%d)\n", linkedContentType, fieldUsage.linked[linkedContentType])
+			}
+			if fieldUsage.broken > 0 {
+				fmt.Printf("    broken:   %d links to missing entries\n", fieldUsage.broken)
+			}
+			notAllowed := getNotAllowed(linked, fieldUsage.allowed)
+			switch {
+			case len(notAllowed) > 0:
+				fmt.Printf("    warning:  links to %s were created before the validation\n", strings.Join(notAllowed, ","))
+			case len(linked) > 0 && (len(fieldUsage.allowed) == 0 || len(linked) < len(fieldUsage.allowed)):
+				fmt.Printf("    proposed: %s\n", strings.Join(linked, ","))
+				proposals++
+			}
+		}
+	}
+	fmt.Printf("%d reference fields could get a tighter linkContentType validation\n", proposals)
+}
+
+func getNotAllowed(linked, allowed []string) []string {
+	if len(allowed) == 0 {
+		return nil
+	}
+	var notAllowed []string
+	for _, linkedContentType := range linked {
+		found := false
+		for _, allowedContentType := range allowed {
+			if allowedContentType == linkedContentType {
+				found = true
+				break
+			}
+		}
+		if !found {
+			notAllowed = append(notAllowed, linkedContentType)
+		}
+	}
+	return notAllowed
+}
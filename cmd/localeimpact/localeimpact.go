@@ -0,0 +1,193 @@
+package localeimpact
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"sort"
+
+	"github.com/foomo/contentful"
+
+	"github.com/foomo/contentfulcommander/cmd/common"
+	"github.com/foomo/contentfulcommander/contentfulclient"
+)
+
+func Run(cma *contentful.Contentful, params []string) error {
+	flagSet := flag.NewFlagSet("localeimpact", flag.ContinueOnError)
+	copyTo := flagSet.String("copyto", "", "copy the data to this locale where it has no value yet")
+	strip := flagSet.Bool("strip", false, "remove the data of the locale from all entries and assets")
+	err := flagSet.Parse(params)
+	if err != nil {
+		return err
+	}
+	if flagSet.NArg() != 2 {
+		return errors.New("localeimpact needs space and locale")
+	}
+	spaceID, environment := contentfulclient.GetSpaceAndEnvironment(flagSet.Arg(0))
+	cma.Environment = environment
+	locale := flagSet.Arg(1)
+	operations := []contentfulclient.Operation{contentfulclient.OperationRead}
+	if *copyTo != "" || *strip {
+		operations = append(operations, contentfulclient.OperationUpdate, contentfulclient.OperationPublish)
+	}
+	err = contentfulclient.Preflight(context.Background(), cma, spaceID, environment, operations...)
+	if err != nil {
+		return err
+	}
+	err = checkLocales(cma, spaceID, locale, *copyTo)
+	if err != nil {
+		return err
+	}
+
+	entries, err := common.GetAllEntries(cma, spaceID)
+	if err != nil {
+		return err
+	}
+	assets, err := common.GetAllAssets(cma, spaceID)
+	if err != nil {
+		return err
+	}
+	fieldCounts := map[string]int{}
+	var affectedEntries []*contentful.Entry
+	for _, entry := range entries {
+		affected := false
+		for fieldID, field := range entry.Fields {
+			localizedValue, ok := field.(map[string]any)
+			if !ok {
+				continue
+			}
+			if _, ok := localizedValue[locale]; ok {
+				fieldCounts[entry.Sys.ContentType.Sys.ID+"."+fieldID]++
+				affected = true
+			}
+		}
+		if affected {
+			affectedEntries = append(affectedEntries, entry)
+		}
+	}
+	var affectedAssets []*contentful.Asset
+	for _, asset := range assets {
+		if asset.Fields == nil {
+			continue
+		}
+		_, hasTitle := asset.Fields.Title[locale]
+		_, hasDescription := asset.Fields.Description[locale]
+		_, hasFile := asset.Fields.File[locale]
+		if hasTitle || hasDescription || hasFile {
+			affectedAssets = append(affectedAssets, asset)
+		}
+	}
+	printReport(locale, fieldCounts, len(affectedEntries), len(entries), len(affectedAssets), len(assets))
+	if *copyTo == "" && !*strip {
+		return nil
+	}
+
+	failed := 0
+	for _, entry := range affectedEntries {
+		for _, field := range entry.Fields {
+			localizedValue, ok := field.(map[string]any)
+			if !ok {
+				continue
+			}
+			value, ok := localizedValue[locale]
+			if !ok {
+				continue
+			}
+			if _, hasTarget := localizedValue[*copyTo]; *copyTo != "" && !hasTarget {
+				localizedValue[*copyTo] = value
+			}
+			if *strip {
+				delete(localizedValue, locale)
+			}
+		}
+		err := common.SmartUpdateEntry(entry, nil, cma, spaceID)
+		if err != nil {
+			log.Printf("Entry %s could not be updated: %v", entry.Sys.ID, err)
+			failed++
+		}
+	}
+	failedAssets := 0
+	for _, asset := range affectedAssets {
+		if *copyTo != "" {
+			copyAssetLocale(asset.Fields, locale, *copyTo)
+		}
+		if *strip {
+			delete(asset.Fields.Title, locale)
+			delete(asset.Fields.Description, locale)
+			delete(asset.Fields.File, locale)
+		}
+		err := common.SmartUpdateAsset(asset, cma, spaceID)
+		if err != nil {
+			log.Printf("Asset %s could not be updated: %v", asset.Sys.ID, err)
+			failedAssets++
+		}
+	}
+	if failed > 0 || failedAssets > 0 {
+		return fmt.Errorf("%d of %d entries and %d of %d assets could not be updated",
+			failed, len(affectedEntries), failedAssets, len(affectedAssets))
+	}
+	return nil
+}
+
+// copyAssetLocale copies title, description and file to copyTo where it has no value yet. Processed files
+// can be copied as they are, Contentful keeps serving them from the same URL.
+func copyAssetLocale(fields *contentful.FileFields, locale, copyTo string) {
+	if title, ok := fields.Title[locale]; ok {
+		if _, hasTarget := fields.Title[copyTo]; !hasTarget {
+			fields.Title[copyTo] = title
+		}
+	}
+	if description, ok := fields.Description[locale]; ok {
+		if _, hasTarget := fields.Description[copyTo]; !hasTarget {
+			fields.Description[copyTo] = description
+		}
+	}
+	if file, ok := fields.File[locale]; ok && file != nil {
+		if _, hasTarget := fields.File[copyTo]; !hasTarget {
+			fileCopy := *file
+			fields.File[copyTo] = &fileCopy
+		}
+	}
+}
+
+func checkLocales(cma *contentful.Contentful, spaceID, locale, copyTo string) error {
+	collection, err := contentfulclient.GetAll(func() *contentful.Collection {
+		return cma.Locales.List(spaceID)
+	})
+	if err != nil {
+		return err
+	}
+	locales, err := contentfulclient.DecodeItems[*contentful.Locale](collection)
+	if err != nil {
+		return err
+	}
+	found := map[string]bool{}
+	for _, l := range locales {
+		found[l.Code] = true
+		if l.Code == locale && l.Default {
+			return fmt.Errorf("%s is the default locale and cannot be removed", locale)
+		}
+	}
+	if !found[locale] {
+		return fmt.Errorf("locale %s does not exist in space %s", locale, spaceID)
+	}
+	if copyTo != "" && !found[copyTo] {
+		return fmt.Errorf("locale %s does not exist in space %s", copyTo, spaceID)
+	}
+	return nil
+}
+
+func printReport(locale string, fieldCounts map[string]int, affectedEntries, totalEntries, affectedAssets, totalAssets int) {
+	fmt.Printf("Removing locale %s drops data in %d of %d entries and %d of %d assets\n",
+		locale, affectedEntries, totalEntries, affectedAssets, totalAssets)
+	fields := make([]string, 0, len(fieldCounts))
+	for field := range fieldCounts {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	for _, field := range fields {
+		fmt.Printf("    %-48s %6d entries\n", field, fieldCounts[field])
+	}
+}
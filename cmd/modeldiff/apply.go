@@ -0,0 +1,168 @@
+package modeldiff
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/foomo/contentful"
+
+	"github.com/foomo/contentfulcommander/cmd/common"
+	"github.com/foomo/contentfulcommander/contentfulclient"
+	"github.com/foomo/contentfulcommander/model"
+)
+
+// rawContentType keeps the fields as they come from the API. Decoding them with the contentful package
+// drops validations it does not know, which must not happen when writing a content type back.
+type rawContentType struct {
+	Sys          model.ContentfulSys `json:"sys"`
+	Name         string              `json:"name"`
+	Description  string              `json:"description,omitempty"`
+	DisplayField string              `json:"displayField,omitempty"`
+	Fields       []json.RawMessage   `json:"fields"`
+}
+
+type contentTypeChange struct {
+	source *rawContentType
+	target *rawContentType
+}
+
+// applyContentTypes makes the content types of the second space match the first one. Fields that only
+// exist in the second space are kept, removing them needs omitting and deleting them in two steps.
+func applyContentTypes(cma *contentful.Contentful, firstSpace, firstEnvironment, secondSpace, secondEnvironment string,
+	firstContentTypes, secondContentTypes []model.ContentType, dryRun, yes bool,
+) error {
+	changedIDs := getChangedContentTypeIDs(firstContentTypes, secondContentTypes)
+	if len(changedIDs) == 0 {
+		log.Printf("The content model of %s/%s already matches %s/%s", secondSpace, secondEnvironment, firstSpace, firstEnvironment)
+		return nil
+	}
+	sources, err := getRawContentTypes(cma, firstSpace, firstEnvironment)
+	if err != nil {
+		return err
+	}
+	targets, err := getRawContentTypes(cma, secondSpace, secondEnvironment)
+	if err != nil {
+		return err
+	}
+	changes := make([]contentTypeChange, 0, len(changedIDs))
+	for _, contentTypeID := range changedIDs {
+		change := contentTypeChange{source: sources[contentTypeID], target: targets[contentTypeID]}
+		if change.target == nil {
+			fmt.Printf("create   %s\n", contentTypeID)
+		} else {
+			fmt.Printf("update   %s\n", contentTypeID)
+		}
+		changes = append(changes, change)
+	}
+	if dryRun {
+		log.Printf("Dry run, %d content types would be changed in %s/%s", len(changes), secondSpace, secondEnvironment)
+		return nil
+	}
+	if !yes && !common.Confirm(fmt.Sprintf("Apply %d content type changes to %s/%s?", len(changes), secondSpace, secondEnvironment)) {
+		return nil
+	}
+	cma.Environment = secondEnvironment
+	failed := 0
+	for _, change := range changes {
+		err := applyContentType(cma, secondSpace, change)
+		if err != nil {
+			log.Printf("Content type %s could not be applied: %v", change.source.Sys.ID, err)
+			failed++
+			continue
+		}
+		log.Printf("Content type %s was applied and activated", change.source.Sys.ID)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d content types could not be applied", failed, len(changes))
+	}
+	return nil
+}
+
+func getChangedContentTypeIDs(firstContentTypes, secondContentTypes []model.ContentType) []string {
+	secondByID := map[string]model.ContentType{}
+	for _, contentType := range secondContentTypes {
+		secondByID[contentType.Sys.ID] = contentType
+	}
+	var changedIDs []string
+	for _, first := range firstContentTypes {
+		second, ok := secondByID[first.Sys.ID]
+		if !ok || first.Name != second.Name || first.Description != second.Description {
+			changedIDs = append(changedIDs, first.Sys.ID)
+			continue
+		}
+		secondFields := map[string]string{}
+		for _, field := range second.Fields {
+			secondFields[field.ID] = getJSONString(field)
+		}
+		for _, field := range first.Fields {
+			if secondFields[field.ID] != getJSONString(field) {
+				changedIDs = append(changedIDs, first.Sys.ID)
+				break
+			}
+		}
+	}
+	sort.Strings(changedIDs)
+	return changedIDs
+}
+
+func getRawContentTypes(cma *contentful.Contentful, spaceID, environment string) (map[string]*rawContentType, error) {
+	cma.Environment = environment
+	col, err := contentfulclient.GetAll(func() *contentful.Collection {
+		return cma.ContentTypes.List(spaceID)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not get content types for %s/%s: %v", spaceID, environment, err)
+	}
+	contentTypes, err := contentfulclient.DecodeItems[*rawContentType](col)
+	if err != nil {
+		return nil, err
+	}
+	contentTypeMap := make(map[string]*rawContentType, len(contentTypes))
+	for _, contentType := range contentTypes {
+		contentTypeMap[contentType.Sys.ID] = contentType
+	}
+	return contentTypeMap, nil
+}
+
+func applyContentType(cma *contentful.Contentful, spaceID string, change contentTypeChange) error {
+	payload := rawContentType{
+		Name:         change.source.Name,
+		Description:  change.source.Description,
+		DisplayField: change.source.DisplayField,
+		Fields:       change.source.Fields,
+	}
+	headers := map[string]string{}
+	if change.target != nil {
+		headers["X-Contentful-Version"] = strconv.Itoa(int(change.target.Sys.Version))
+		sourceFieldIDs := map[string]bool{}
+		for _, field := range change.source.Fields {
+			sourceFieldIDs[getFieldID(field)] = true
+		}
+		for _, field := range change.target.Fields {
+			if !sourceFieldIDs[getFieldID(field)] {
+				payload.Fields = append(payload.Fields, field)
+			}
+		}
+	}
+	path := fmt.Sprintf("/spaces/%s/environments/%s/content_types/%s", spaceID, cma.Environment, change.source.Sys.ID)
+	var updated rawContentType
+	err := contentfulclient.DoWithHeaders(context.Background(), cma, http.MethodPut, path, headers, payload, &updated)
+	if err != nil {
+		return err
+	}
+	return contentfulclient.DoWithHeaders(context.Background(), cma, http.MethodPut, path+"/published",
+		map[string]string{"X-Contentful-Version": strconv.Itoa(int(updated.Sys.Version))}, nil, nil)
+}
+
+func getFieldID(field json.RawMessage) string {
+	var idOnly struct {
+		ID string `json:"id"`
+	}
+	_ = json.Unmarshal(field, &idOnly)
+	return idOnly.ID
+}
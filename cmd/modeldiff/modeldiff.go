@@ -1,9 +1,10 @@
 package modeldiff
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
+	"flag"
 	"fmt"
 	"sort"
 	"strings"
@@ -15,6 +16,18 @@ import (
 )
 
 func Run(cma *contentful.Contentful, params []string) error {
+	flagSet := flag.NewFlagSet("modeldiff", flag.ContinueOnError)
+	apply := flagSet.Bool("apply", false, "make the content model of the second space match the first one")
+	dryRun := flagSet.Bool("dryrun", false, "with apply, only list the content types that would be changed")
+	yes := flagSet.Bool("yes", false, "with apply, do not ask for confirmation")
+	err := flagSet.Parse(params)
+	if err != nil {
+		return err
+	}
+	if flagSet.NArg() != 2 {
+		return errors.New("modeldiff needs exactly two space parameters")
+	}
+	params = flagSet.Args()
 	firstSpace, firstEnvironment := contentfulclient.GetSpaceAndEnvironment(params[0])
 	if firstSpace == "" {
 		return errors.New("firstspace ID is empty")
@@ -29,6 +42,18 @@ func Run(cma *contentful.Contentful, params []string) error {
 	if secondEnvironment == "" {
 		return errors.New("secondEnvironment ID is empty")
 	}
+	err = contentfulclient.Preflight(context.Background(), cma, firstSpace, firstEnvironment, contentfulclient.OperationRead)
+	if err != nil {
+		return err
+	}
+	secondOperation := contentfulclient.OperationRead
+	if *apply && !*dryRun {
+		secondOperation = contentfulclient.OperationModel
+	}
+	err = contentfulclient.Preflight(context.Background(), cma, secondSpace, secondEnvironment, secondOperation)
+	if err != nil {
+		return err
+	}
 	fmt.Printf("A: %s/%s B: %s/%s\n", firstSpace, firstEnvironment, secondSpace, secondEnvironment)
 
 	firstSpaceContentTypes, err := getContentTypes(cma, firstSpace, firstEnvironment)
@@ -43,23 +68,26 @@ func Run(cma *contentful.Contentful, params []string) error {
 		fmt.Sprintf("%s/%s", secondSpace, secondEnvironment),
 		firstSpaceContentTypes,
 		secondSpaceContentTypes)
-	return nil
+	if !*apply {
+		return nil
+	}
+	return applyContentTypes(cma, firstSpace, firstEnvironment, secondSpace, secondEnvironment,
+		firstSpaceContentTypes, secondSpaceContentTypes, *dryRun, *yes)
 }
 
 func getContentTypes(cma *contentful.Contentful, spaceID, environment string) (contentTypes []model.ContentType, err error) {
 	cma.Environment = environment
-	col := cma.ContentTypes.List(spaceID)
-	_, errGetAll := col.GetAll()
+	col, errGetAll := contentfulclient.GetAll(func() *contentful.Collection {
+		return cma.ContentTypes.List(spaceID)
+	})
 	if errGetAll != nil {
-		err = fmt.Errorf("could not get content types for %s/%s: %v", spaceID, environment, errGetAll)
+		return nil, fmt.Errorf("could not get content types for %s/%s: %v", spaceID, environment, errGetAll)
 	}
-	for _, item := range col.Items {
-		var contentType model.ContentType
-		byteArray, _ := json.Marshal(item)
-		err = json.NewDecoder(bytes.NewReader(byteArray)).Decode(&contentType)
-		if err != nil {
-			break
-		}
+	decodedContentTypes, err := contentfulclient.DecodeItems[model.ContentType](col)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode content types for %s/%s: %v", spaceID, environment, err)
+	}
+	for _, contentType := range decodedContentTypes {
 		var filteredFields []model.ContentTypeField
 		for _, field := range contentType.Fields {
 			if !field.Omitted {
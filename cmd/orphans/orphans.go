@@ -0,0 +1,168 @@
+package orphans
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"sort"
+
+	"github.com/foomo/contentful"
+
+	"github.com/foomo/contentfulcommander/cmd/common"
+	"github.com/foomo/contentfulcommander/contentfulclient"
+)
+
+func Run(cma *contentful.Contentful, params []string) error {
+	flagSet := flag.NewFlagSet("orphans", flag.ContinueOnError)
+	contentTypeID := flagSet.String("contenttype", "", "only list orphans of this content type")
+	unpublish := flagSet.Bool("unpublish", false, "unpublish the orphans")
+	archive := flagSet.Bool("archive", false, "unpublish and archive the orphans")
+	yes := flagSet.Bool("yes", false, "do not ask for confirmation before unpublishing or archiving")
+	bulk := flagSet.Bool("bulk", false, "unpublish with bulk actions of up to 200 entries instead of one request per entry")
+	err := flagSet.Parse(params)
+	if err != nil {
+		return err
+	}
+	if flagSet.NArg() != 1 {
+		return errors.New("orphans needs exactly one space parameter")
+	}
+	spaceID, environment := contentfulclient.GetSpaceAndEnvironment(flagSet.Arg(0))
+	cma.Environment = environment
+	operations := []contentfulclient.Operation{contentfulclient.OperationRead}
+	if *unpublish || *archive {
+		operations = append(operations, contentfulclient.OperationPublish)
+	}
+	if *archive {
+		operations = append(operations, contentfulclient.OperationArchive)
+	}
+	err = contentfulclient.Preflight(context.Background(), cma, spaceID, environment, operations...)
+	if err != nil {
+		return err
+	}
+	// all entries are needed for the reverse references, even when filtering by content type. Archived
+	// entries neither count as orphans nor keep the entries they link to from being orphans.
+	collection, err := contentfulclient.GetAll(func() *contentful.Collection {
+		collection := cma.Entries.List(spaceID)
+		collection.Query.NotExists("sys.archivedAt")
+		return collection
+	})
+	if err != nil {
+		return err
+	}
+	entries, err := contentfulclient.DecodeItems[*contentful.Entry](collection)
+	if err != nil {
+		return err
+	}
+	log.Printf("Loaded %d entries, building reverse references", len(entries))
+	linked := map[string]bool{}
+	for _, entry := range entries {
+		for _, reference := range common.GetOutboundReferences(entry) {
+			if reference.LinkType == "Entry" && reference.ID != entry.Sys.ID {
+				linked[reference.ID] = true
+			}
+		}
+	}
+	var orphans []*contentful.Entry
+	for _, entry := range entries {
+		if linked[entry.Sys.ID] || (*contentTypeID != "" && entry.Sys.ContentType.Sys.ID != *contentTypeID) {
+			continue
+		}
+		orphans = append(orphans, entry)
+	}
+	sort.Slice(orphans, func(i, j int) bool {
+		if orphans[i].Sys.ContentType.Sys.ID != orphans[j].Sys.ContentType.Sys.ID {
+			return orphans[i].Sys.ContentType.Sys.ID < orphans[j].Sys.ContentType.Sys.ID
+		}
+		return orphans[i].Sys.ID < orphans[j].Sys.ID
+	})
+	for _, orphan := range orphans {
+		status := "draft"
+		if orphan.Sys.PublishedVersion > 0 {
+			status = "published"
+		}
+		fmt.Printf("%s %s %s https://app.contentful.com/spaces/%s/environments/%s/entries/%s\n",
+			orphan.Sys.ContentType.Sys.ID, orphan.Sys.ID, status, spaceID, environment, orphan.Sys.ID)
+		if previewURL := common.PreviewURL(orphan); previewURL != "" {
+			fmt.Printf("    preview: %s\n", previewURL)
+		}
+	}
+	log.Printf("Found %d entries that no other entry links to", len(orphans))
+	if (!*unpublish && !*archive) || len(orphans) == 0 {
+		return nil
+	}
+	action := "Unpublish"
+	if *archive {
+		action = "Archive"
+	}
+	if !*yes && !common.Confirm(fmt.Sprintf("%s %d orphans in %s/%s?", action, len(orphans), spaceID, environment)) {
+		return nil
+	}
+	if *bulk {
+		err = bulkUnpublish(cma, spaceID, orphans)
+		if err != nil || !*archive {
+			return err
+		}
+	}
+	failed := 0
+	for _, orphan := range orphans {
+		err := retire(cma, spaceID, orphan, !*bulk, *archive)
+		if err != nil {
+			log.Printf("Entry %s could not be retired: %v", orphan.Sys.ID, err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d orphans could not be retired", failed, len(orphans))
+	}
+	return nil
+}
+
+// retire unpublishes an entry unless that already happened in bulk and archives it if requested, only
+// unpublished entries can be archived
+func retire(cma *contentful.Contentful, spaceID string, entry *contentful.Entry, unpublish, archive bool) error {
+	if unpublish && entry.Sys.PublishedVersion > 0 {
+		err := cma.Entries.Unpublish(spaceID, entry)
+		if err != nil {
+			return err
+		}
+		log.Printf("Entry %s was unpublished", entry.Sys.ID)
+	}
+	if !archive {
+		return nil
+	}
+	// unpublishing changed the version
+	entry, err := cma.Entries.Get(spaceID, entry.Sys.ID)
+	if err != nil {
+		return err
+	}
+	if entry == nil {
+		return errors.New("entry could not be loaded again after unpublishing")
+	}
+	err = cma.Entries.Archive(spaceID, entry)
+	if err != nil {
+		return err
+	}
+	log.Printf("Entry %s was archived", entry.Sys.ID)
+	return nil
+}
+
+func bulkUnpublish(cma *contentful.Contentful, spaceID string, entries []*contentful.Entry) error {
+	var links []contentfulclient.BulkLink
+	for _, entry := range entries {
+		if entry.Sys.PublishedVersion > 0 {
+			links = append(links, contentfulclient.BulkLink{ID: entry.Sys.ID, LinkType: "Entry"})
+		}
+	}
+	if len(links) == 0 {
+		log.Print("No published orphans found")
+		return nil
+	}
+	err := contentfulclient.BulkUnpublish(context.Background(), cma, spaceID, links)
+	if err != nil {
+		return err
+	}
+	log.Printf("%d orphans unpublished with bulk actions", len(links))
+	return nil
+}
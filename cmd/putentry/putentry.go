@@ -0,0 +1,127 @@
+package putentry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/foomo/contentful"
+
+	"github.com/foomo/contentfulcommander/contentfulclient"
+)
+
+type sys struct {
+	ID               string `json:"id"`
+	Version          int    `json:"version"`
+	PublishedVersion int    `json:"publishedVersion,omitempty"`
+	ArchivedVersion  int    `json:"archivedVersion,omitempty"`
+	ContentType      *struct {
+		Sys struct {
+			ID string `json:"id"`
+		} `json:"sys"`
+	} `json:"contentType,omitempty"`
+}
+
+type entry struct {
+	Sys      sys             `json:"sys"`
+	Metadata json.RawMessage `json:"metadata,omitempty"`
+	Fields   json.RawMessage `json:"fields"`
+}
+
+func Run(cma *contentful.Contentful, params []string) error {
+	flagSet := flag.NewFlagSet("putentry", flag.ContinueOnError)
+	fileName := flagSet.String("file", "", "JSON file with the entry as written by getentry")
+	publish := flagSet.Bool("publish", false, "publish the entry, also if it was a draft before")
+	err := flagSet.Parse(params)
+	if err != nil {
+		return err
+	}
+	if flagSet.NArg() != 1 || *fileName == "" {
+		return errors.New("putentry needs a file and a space")
+	}
+	source, err := readEntry(*fileName)
+	if err != nil {
+		return err
+	}
+	spaceID, environment := contentfulclient.GetSpaceAndEnvironment(flagSet.Arg(0))
+	ctx := context.Background()
+	err = contentfulclient.Preflight(ctx, cma, spaceID, environment, contentfulclient.OperationUpdate, contentfulclient.OperationPublish)
+	if err != nil {
+		return err
+	}
+	path := fmt.Sprintf("/spaces/%s/environments/%s/entries/%s", spaceID, environment, source.Sys.ID)
+	var target *entry
+	err = contentfulclient.Do(ctx, cma, http.MethodGet, path, nil, &target)
+	if err != nil && !isNotFound(err) {
+		return fmt.Errorf("could not get entry %s: %v", source.Sys.ID, err)
+	}
+	headers := map[string]string{"X-Contentful-Content-Type": source.Sys.ContentType.Sys.ID}
+	wasPublished := false
+	if target != nil {
+		if target.Sys.ArchivedVersion > 0 {
+			return fmt.Errorf("entry %s is archived, unarchive it first", source.Sys.ID)
+		}
+		if target.Sys.ContentType != nil && target.Sys.ContentType.Sys.ID != source.Sys.ContentType.Sys.ID {
+			return fmt.Errorf("entry %s has content type %s and not %s", source.Sys.ID,
+				target.Sys.ContentType.Sys.ID, source.Sys.ContentType.Sys.ID)
+		}
+		headers["X-Contentful-Version"] = strconv.Itoa(target.Sys.Version)
+		wasPublished = target.Sys.PublishedVersion > 0 && target.Sys.Version-target.Sys.PublishedVersion == 1
+	}
+	payload := map[string]any{"fields": source.Fields}
+	if len(source.Metadata) > 0 {
+		payload["metadata"] = source.Metadata
+	}
+	var updated entry
+	err = contentfulclient.DoWithHeaders(ctx, cma, http.MethodPut, path, headers, payload, &updated)
+	if err != nil {
+		return fmt.Errorf("could not put entry %s: %v", source.Sys.ID, err)
+	}
+	if target == nil {
+		log.Printf("Entry %s was created", source.Sys.ID)
+	} else {
+		log.Printf("Entry %s was updated", source.Sys.ID)
+	}
+	if !*publish && !wasPublished {
+		return nil
+	}
+	err = contentfulclient.DoWithHeaders(ctx, cma, http.MethodPut, path+"/published",
+		map[string]string{"X-Contentful-Version": strconv.Itoa(updated.Sys.Version)}, nil, nil)
+	if err != nil {
+		return fmt.Errorf("could not publish entry %s: %v", source.Sys.ID, err)
+	}
+	log.Printf("Entry %s was published", source.Sys.ID)
+	return nil
+}
+
+// readEntry reads an entry in the format of the API. Only the ID and content type of its sys are used, so
+// an entry can be copied to another space or environment.
+func readEntry(fileName string) (*entry, error) {
+	byt, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	var e entry
+	err = json.Unmarshal(byt, &e)
+	if err != nil {
+		return nil, fmt.Errorf("could not read entry from %s: %v", fileName, err)
+	}
+	if e.Sys.ID == "" {
+		return nil, fmt.Errorf("entry in %s has no sys.id", fileName)
+	}
+	if e.Sys.ContentType == nil || e.Sys.ContentType.Sys.ID == "" {
+		return nil, fmt.Errorf("entry in %s has no content type", fileName)
+	}
+	return &e, nil
+}
+
+func isNotFound(err error) bool {
+	var apiError contentfulclient.APIError
+	return errors.As(err, &apiError) && apiError.StatusCode == http.StatusNotFound
+}
@@ -0,0 +1,114 @@
+package relink
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/foomo/contentful"
+
+	"github.com/foomo/contentfulcommander/cmd/common"
+	"github.com/foomo/contentfulcommander/contentfulclient"
+	"github.com/foomo/contentfulcommander/model"
+)
+
+func Run(cma *contentful.Contentful, params []string) error {
+	flagSet := flag.NewFlagSet("relink", flag.ContinueOnError)
+	fromID := flagSet.String("from", "", "ID of the entry or asset the references point to now")
+	toID := flagSet.String("to", "", "ID of the entry or asset the references should point to")
+	fieldID := flagSet.String("field", "", "only change references in this field")
+	dryRun := flagSet.Bool("dryrun", false, "only list the entries that would be changed")
+	err := flagSet.Parse(params)
+	if err != nil {
+		return err
+	}
+	if flagSet.NArg() != 1 || *fromID == "" || *toID == "" {
+		return errors.New("relink needs -from, -to and exactly one space parameter")
+	}
+	spaceID, environment := contentfulclient.GetSpaceAndEnvironment(flagSet.Arg(0))
+	cma.Environment = environment
+	operations := []contentfulclient.Operation{contentfulclient.OperationUpdate, contentfulclient.OperationPublish}
+	if *dryRun {
+		operations = []contentfulclient.Operation{contentfulclient.OperationRead}
+	}
+	err = contentfulclient.Preflight(context.Background(), cma, spaceID, environment, operations...)
+	if err != nil {
+		return err
+	}
+	fromLinkType, err := getLinkType(cma, spaceID, *fromID)
+	if err != nil {
+		return err
+	}
+	toLinkType, err := getLinkType(cma, spaceID, *toID)
+	if err != nil {
+		return err
+	}
+	if fromLinkType != toLinkType {
+		return fmt.Errorf("%s is an %s and %s is an %s, references can only be moved between entities of the same kind",
+			*fromID, fromLinkType, *toID, toLinkType)
+	}
+
+	linksTo := "links_to_entry"
+	if fromLinkType == "Asset" {
+		linksTo = "links_to_asset"
+	}
+	collection, err := contentfulclient.GetAll(func() *contentful.Collection {
+		collection := cma.Entries.List(spaceID)
+		collection.Query.Equal(linksTo, *fromID)
+		return collection
+	})
+	if err != nil {
+		return err
+	}
+	parents, err := contentfulclient.DecodeItems[*contentful.Entry](collection)
+	if err != nil {
+		return err
+	}
+	log.Printf("Found %d entries linking to %s %s", len(parents), fromLinkType, *fromID)
+	from := model.ReferenceSysAttributes{ID: *fromID, Type: "Link", LinkType: fromLinkType}
+	changed, failed := 0, 0
+	for _, parent := range parents {
+		links := common.RelinkReferences(parent, from, *toID, *fieldID)
+		if links == 0 {
+			continue
+		}
+		changed++
+		if *dryRun {
+			log.Printf("Entry %s (%s) would get %d links changed", parent.Sys.ID, parent.Sys.ContentType.Sys.ID, links)
+			continue
+		}
+		err := common.SmartUpdateEntry(parent, nil, cma, spaceID)
+		if err != nil {
+			log.Printf("Entry %s could not be updated: %v", parent.Sys.ID, err)
+			failed++
+		}
+	}
+	if *dryRun {
+		log.Printf("Dry run: %d entries would be changed", changed)
+		return nil
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d entries could not be updated", failed, changed)
+	}
+	log.Printf("Moved the references of %d entries from %s to %s", changed, *fromID, *toID)
+	return nil
+}
+
+// getLinkType tells whether the ID belongs to an entry or an asset
+func getLinkType(cma *contentful.Contentful, spaceID, id string) (string, error) {
+	basePath := fmt.Sprintf("/spaces/%s/environments/%s", spaceID, cma.Environment)
+	for _, kind := range []struct{ path, linkType string }{{"entries", "Entry"}, {"assets", "Asset"}} {
+		err := contentfulclient.Do(context.Background(), cma, http.MethodGet, basePath+"/"+kind.path+"/"+id, nil, nil)
+		if err == nil {
+			return kind.linkType, nil
+		}
+		var apiError contentfulclient.APIError
+		if !errors.As(err, &apiError) || apiError.StatusCode != http.StatusNotFound {
+			return "", err
+		}
+	}
+	return "", fmt.Errorf("there is no entry or asset %s", id)
+}
@@ -0,0 +1,125 @@
+package republish
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+
+	"github.com/foomo/contentful"
+
+	"github.com/foomo/contentfulcommander/cmd/common"
+	"github.com/foomo/contentfulcommander/contentfulclient"
+)
+
+func Run(cma *contentful.Contentful, params []string) error {
+	flagSet := flag.NewFlagSet("republish", flag.ContinueOnError)
+	dryRun := flagSet.Bool("dryrun", false, "only list the changed entities")
+	contentTypeID := flagSet.String("contenttype", "", "only republish entries of this content type")
+	skipAssets := flagSet.Bool("skipassets", false, "do not republish assets")
+	bulk := flagSet.Bool("bulk", false, "publish with bulk actions of up to 200 entities instead of one request per entity")
+	err := flagSet.Parse(params)
+	if err != nil {
+		return err
+	}
+	if flagSet.NArg() != 1 {
+		return errors.New("republish needs exactly one space parameter")
+	}
+	spaceID, environment := contentfulclient.GetSpaceAndEnvironment(flagSet.Arg(0))
+	cma.Environment = environment
+	operation := contentfulclient.OperationPublish
+	if *dryRun {
+		operation = contentfulclient.OperationRead
+	}
+	err = contentfulclient.Preflight(context.Background(), cma, spaceID, environment, operation)
+	if err != nil {
+		return err
+	}
+
+	entries, err := common.GetEntriesByContentType(cma, spaceID, *contentTypeID)
+	if err != nil {
+		return err
+	}
+	var assets []*contentful.Asset
+	if !*skipAssets && *contentTypeID == "" {
+		assets, err = common.GetAllAssets(cma, spaceID)
+		if err != nil {
+			return err
+		}
+	}
+	var changedEntries []*contentful.Entry
+	for _, entry := range entries {
+		if common.IsChanged(entry.Sys) {
+			changedEntries = append(changedEntries, entry)
+		}
+	}
+	if *bulk && !*dryRun {
+		return bulkRepublish(cma, spaceID, assets, common.SortByDependencies(changedEntries))
+	}
+	published, failed := 0, 0
+	// assets and linked entries are published before the entries linking to them
+	for _, asset := range assets {
+		if !common.IsChanged(asset.Sys) {
+			continue
+		}
+		if *dryRun {
+			log.Printf("Asset %s would be re-published", asset.Sys.ID)
+			published++
+			continue
+		}
+		err := cma.Assets.Publish(spaceID, asset)
+		if err != nil {
+			log.Printf("Asset %s could not be re-published: %v", asset.Sys.ID, err)
+			failed++
+			continue
+		}
+		log.Printf("Asset %s was re-published", asset.Sys.ID)
+		published++
+	}
+	for _, entry := range common.SortByDependencies(changedEntries) {
+		if *dryRun {
+			log.Printf("Entry %s (%s) would be re-published", entry.Sys.ID, entry.Sys.ContentType.Sys.ID)
+			published++
+			continue
+		}
+		err := cma.Entries.Publish(spaceID, entry)
+		if err != nil {
+			log.Printf("Entry %s could not be re-published: %v", entry.Sys.ID, err)
+			failed++
+			continue
+		}
+		log.Printf("Entry %s was re-published", entry.Sys.ID)
+		published++
+	}
+	if *dryRun {
+		log.Printf("Dry run: %d changed entities found, nothing was published", published)
+		return nil
+	}
+	log.Printf("%d entities re-published, %d failed", published, failed)
+	if failed > 0 {
+		return errors.New("some entities could not be re-published")
+	}
+	return nil
+}
+
+func bulkRepublish(cma *contentful.Contentful, spaceID string, assets []*contentful.Asset, entries []*contentful.Entry) error {
+	var links []contentfulclient.BulkLink
+	for _, asset := range assets {
+		if common.IsChanged(asset.Sys) {
+			links = append(links, contentfulclient.BulkLink{ID: asset.Sys.ID, LinkType: "Asset", Version: asset.Sys.Version})
+		}
+	}
+	for _, entry := range entries {
+		links = append(links, contentfulclient.BulkLink{ID: entry.Sys.ID, LinkType: "Entry", Version: entry.Sys.Version})
+	}
+	if len(links) == 0 {
+		log.Print("No changed entities found")
+		return nil
+	}
+	err := contentfulclient.BulkPublish(context.Background(), cma, spaceID, links)
+	if err != nil {
+		return err
+	}
+	log.Printf("%d entities re-published with bulk actions", len(links))
+	return nil
+}
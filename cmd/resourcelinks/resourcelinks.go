@@ -0,0 +1,83 @@
+package resourcelinks
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+
+	"github.com/foomo/contentful"
+
+	"github.com/foomo/contentfulcommander/cmd/common"
+	"github.com/foomo/contentfulcommander/contentfulclient"
+	"github.com/foomo/contentfulcommander/model"
+)
+
+func Run(cma *contentful.Contentful, params []string) error {
+	flagSet := flag.NewFlagSet("resourcelinks", flag.ContinueOnError)
+	targetSpaceID := flagSet.String("target", "", "only show cross-space references into this space")
+	localize := flagSet.String("localize", "", "replace cross-space references into this space with local references")
+	err := flagSet.Parse(params)
+	if err != nil {
+		return err
+	}
+	if flagSet.NArg() != 1 {
+		return errors.New("resourcelinks needs exactly one space parameter")
+	}
+	spaceID, environment := contentfulclient.GetSpaceAndEnvironment(flagSet.Arg(0))
+	cma.Environment = environment
+	operations := []contentfulclient.Operation{contentfulclient.OperationRead}
+	if *localize != "" {
+		operations = append(operations, contentfulclient.OperationUpdate, contentfulclient.OperationPublish)
+	}
+	err = contentfulclient.Preflight(context.Background(), cma, spaceID, environment, operations...)
+	if err != nil {
+		return err
+	}
+	entries, err := common.GetAllEntries(cma, spaceID)
+	if err != nil {
+		return err
+	}
+	found := 0
+	var entriesToLocalize []*contentful.Entry
+	for _, entry := range entries {
+		linksToLocalize := 0
+		common.WalkResourceLinks(entry, func(fieldID, locale string, link model.ResourceLink) {
+			urn, err := common.ParseResourceURN(link.Sys.URN)
+			if err != nil {
+				log.Printf("Entry %s field %s/%s: %v", entry.Sys.ID, fieldID, locale, err)
+				return
+			}
+			if *localize != "" && urn.SpaceID == *localize {
+				linksToLocalize++
+			}
+			if *targetSpaceID != "" && urn.SpaceID != *targetSpaceID {
+				return
+			}
+			found++
+			fmt.Printf("%s %s %s/%s -> %s\n", entry.Sys.ContentType.Sys.ID, entry.Sys.ID, fieldID, locale, link.Sys.URN)
+		})
+		if linksToLocalize > 0 {
+			entriesToLocalize = append(entriesToLocalize, entry)
+		}
+	}
+	log.Printf("Found %d cross-space references in %d scanned entries", found, len(entries))
+	if *localize == "" {
+		return nil
+	}
+	failed := 0
+	for _, entry := range entriesToLocalize {
+		replaced := common.LocalizeResourceLinks(entry, *localize)
+		log.Printf("Entry %s: replacing %d cross-space references", entry.Sys.ID, replaced)
+		err := common.SmartUpdateEntry(entry, nil, cma, spaceID)
+		if err != nil {
+			log.Printf("Entry %s could not be updated: %v", entry.Sys.ID, err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d entries could not be updated", failed, len(entriesToLocalize))
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package roles
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/foomo/contentful"
+
+	"github.com/foomo/contentfulcommander/contentfulclient"
+)
+
+func Run(cma *contentful.Contentful, params []string) error {
+	// roles belong to the space, an environment in the space parameter is ignored
+	spaceID, _ := contentfulclient.GetSpaceAndEnvironment(params[1])
+	ctx := context.Background()
+	operation := contentfulclient.OperationRead
+	if params[0] == "create" || params[0] == "update" {
+		operation = contentfulclient.OperationUpdate
+	}
+	err := contentfulclient.PreflightSpace(ctx, cma, spaceID, operation)
+	if err != nil {
+		return err
+	}
+	switch params[0] {
+	case "list":
+		roles, err := contentfulclient.ListRoles(ctx, cma, spaceID)
+		if err != nil {
+			return err
+		}
+		for _, role := range roles {
+			fmt.Printf("%s %q %s\n", role.Sys.ID, role.Name, role.Description)
+		}
+		return nil
+	case "create":
+		if len(params) != 3 {
+			return errors.New("roles create needs space and file")
+		}
+		role, err := readRole(params[2])
+		if err != nil {
+			return err
+		}
+		err = contentfulclient.UpsertRole(ctx, cma, spaceID, role)
+		if err != nil {
+			return err
+		}
+		log.Printf("Role %s %q was created", role.Sys.ID, role.Name)
+		return nil
+	case "update":
+		if len(params) != 4 {
+			return errors.New("roles update needs space, role ID and file")
+		}
+		existingRole, err := contentfulclient.GetRole(ctx, cma, spaceID, params[2])
+		if err != nil {
+			return err
+		}
+		role, err := readRole(params[3])
+		if err != nil {
+			return err
+		}
+		role.Sys = existingRole.Sys
+		err = contentfulclient.UpsertRole(ctx, cma, spaceID, role)
+		if err != nil {
+			return err
+		}
+		log.Printf("Role %s %q was updated", role.Sys.ID, role.Name)
+		return nil
+	default:
+		return fmt.Errorf("unknown roles subcommand %q, use list, create or update", params[0])
+	}
+}
+
+func readRole(fileName string) (*contentfulclient.Role, error) {
+	byt, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	var role contentfulclient.Role
+	err = json.Unmarshal(byt, &role)
+	if err != nil {
+		return nil, fmt.Errorf("could not read role from %s: %v", fileName, err)
+	}
+	if role.Name == "" {
+		return nil, fmt.Errorf("role in %s has no name", fileName)
+	}
+	// the sys of the file is ignored so that roles can be copied between spaces
+	role.Sys = nil
+	return &role, nil
+}
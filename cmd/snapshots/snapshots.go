@@ -0,0 +1,118 @@
+package snapshots
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"reflect"
+	"sort"
+
+	"github.com/foomo/contentful"
+
+	"github.com/foomo/contentfulcommander/cmd/common"
+	"github.com/foomo/contentfulcommander/contentfulclient"
+)
+
+func Run(cma *contentful.Contentful, params []string) error {
+	spaceID, environment := contentfulclient.GetSpaceAndEnvironment(params[1])
+	entryID := params[2]
+	cma.Environment = environment
+	ctx := context.Background()
+	operations := []contentfulclient.Operation{contentfulclient.OperationRead}
+	if params[0] == "restore" {
+		operations = []contentfulclient.Operation{contentfulclient.OperationUpdate, contentfulclient.OperationPublish}
+	}
+	err := contentfulclient.Preflight(ctx, cma, spaceID, environment, operations...)
+	if err != nil {
+		return err
+	}
+	snapshots, err := contentfulclient.GetEntrySnapshots(ctx, cma, spaceID, entryID)
+	if err != nil {
+		return fmt.Errorf("could not get the snapshots of entry %s: %v", entryID, err)
+	}
+	switch params[0] {
+	case "list":
+		for _, snapshot := range snapshots {
+			createdBy := ""
+			if snapshot.Sys.CreatedBy != nil {
+				createdBy = snapshot.Sys.CreatedBy.Sys.ID
+			}
+			fmt.Printf("%s %s %s %s\n", snapshot.Sys.ID, snapshot.Sys.CreatedAt, snapshot.Sys.SnapshotType, createdBy)
+		}
+		return nil
+	case "show":
+		if len(params) != 4 {
+			return errors.New("snapshots show needs space, entry ID and snapshot ID")
+		}
+		snapshot, err := getSnapshot(snapshots, params[3])
+		if err != nil {
+			return err
+		}
+		byt, err := json.MarshalIndent(snapshot.Snapshot.Fields, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(byt))
+		return nil
+	case "restore":
+		if len(params) != 4 {
+			return errors.New("snapshots restore needs space, entry ID and snapshot ID")
+		}
+		snapshot, err := getSnapshot(snapshots, params[3])
+		if err != nil {
+			return err
+		}
+		return restore(cma, spaceID, entryID, snapshot)
+	default:
+		return fmt.Errorf("unknown snapshots subcommand %q, use list, show or restore", params[0])
+	}
+}
+
+func getSnapshot(snapshots []contentfulclient.Snapshot, snapshotID string) (*contentfulclient.Snapshot, error) {
+	for i, snapshot := range snapshots {
+		if snapshot.Sys.ID == snapshotID && snapshot.Snapshot != nil {
+			return &snapshots[i], nil
+		}
+	}
+	return nil, fmt.Errorf("snapshot %s does not exist", snapshotID)
+}
+
+// restore replaces the fields of the entry with those of the snapshot. The entry is re-published if it
+// was published without pending changes before, otherwise the restored version stays a draft.
+func restore(cma *contentful.Contentful, spaceID, entryID string, snapshot *contentfulclient.Snapshot) error {
+	entry, err := cma.Entries.Get(spaceID, entryID)
+	if err != nil {
+		return fmt.Errorf("could not get entry %s: %v", entryID, err)
+	}
+	if entry == nil {
+		return fmt.Errorf("entry %s does not exist", entryID)
+	}
+	var changed []string
+	for fieldID := range mergeKeys(entry.Fields, snapshot.Snapshot.Fields) {
+		if !reflect.DeepEqual(entry.Fields[fieldID], snapshot.Snapshot.Fields[fieldID]) {
+			changed = append(changed, fieldID)
+		}
+	}
+	if len(changed) == 0 {
+		log.Printf("Entry %s already matches snapshot %s", entryID, snapshot.Sys.ID)
+		return nil
+	}
+	sort.Strings(changed)
+	log.Printf("Restoring fields %v of entry %s from snapshot %s of %s", changed, entryID, snapshot.Sys.ID, snapshot.Sys.CreatedAt)
+	updatedEntry := *entry
+	updatedEntry.Fields = snapshot.Snapshot.Fields
+	return common.SmartUpdateEntry(&updatedEntry, entry, cma, spaceID)
+}
+
+func mergeKeys(a, b map[string]any) map[string]bool {
+	keys := make(map[string]bool, len(a)+len(b))
+	for key := range a {
+		keys[key] = true
+	}
+	for key := range b {
+		keys[key] = true
+	}
+	return keys
+}
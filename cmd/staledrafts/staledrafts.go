@@ -0,0 +1,127 @@
+package staledrafts
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/foomo/contentful"
+
+	"github.com/foomo/contentfulcommander/cmd/common"
+	"github.com/foomo/contentfulcommander/contentfulclient"
+)
+
+const day = 24 * time.Hour
+
+func Run(cma *contentful.Contentful, params []string) error {
+	flagSet := flag.NewFlagSet("staledrafts", flag.ContinueOnError)
+	days := flagSet.Int("days", 90, "drafts not updated for this many days are stale")
+	grace := flagSet.Int("grace", 30, "days after becoming stale before a draft is archived")
+	contentTypeID := flagSet.String("contenttype", "", "only look at entries of this content type")
+	archive := flagSet.Bool("archive", false, "archive stale drafts whose grace period is over")
+	err := flagSet.Parse(params)
+	if err != nil {
+		return err
+	}
+	if flagSet.NArg() != 1 {
+		return errors.New("staledrafts needs exactly one space parameter")
+	}
+	spaceID, environment := contentfulclient.GetSpaceAndEnvironment(flagSet.Arg(0))
+	cma.Environment = environment
+	operation := contentfulclient.OperationRead
+	if *archive {
+		operation = contentfulclient.OperationArchive
+	}
+	err = contentfulclient.Preflight(context.Background(), cma, spaceID, environment, operation)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	staleBefore := now.Add(-time.Duration(*days) * day)
+	archiveBefore := staleBefore.Add(-time.Duration(*grace) * day)
+
+	collection, err := contentfulclient.GetAll(func() *contentful.Collection {
+		collection := cma.Entries.List(spaceID)
+		collection.Query.ContentType(*contentTypeID)
+		collection.Query.NotExists("sys.publishedAt")
+		collection.Query.NotExists("sys.archivedAt")
+		collection.Query.LessThanOrEqual("sys.updatedAt", staleBefore.UTC())
+		return collection
+	})
+	if err != nil {
+		return err
+	}
+	drafts, err := contentfulclient.DecodeItems[*contentful.Entry](collection)
+	if err != nil {
+		return err
+	}
+	log.Printf("Found %d drafts not updated since %s, checking references", len(drafts), staleBefore.Format("2006-01-02"))
+
+	staleByOwner := map[string][]*contentful.Entry{}
+	var dueForArchiving []*contentful.Entry
+	for _, draft := range drafts {
+		parents, err := common.GetEntriesLinkingToThis(cma, spaceID, draft.Sys.ID)
+		if err != nil {
+			return err
+		}
+		if len(parents) > 0 {
+			continue
+		}
+		owner := "unknown"
+		if draft.Sys.UpdatedBy != nil {
+			owner = draft.Sys.UpdatedBy.ID
+		}
+		staleByOwner[owner] = append(staleByOwner[owner], draft)
+		updatedAt, err := time.Parse(time.RFC3339, draft.Sys.UpdatedAt)
+		if err == nil && updatedAt.Before(archiveBefore) {
+			dueForArchiving = append(dueForArchiving, draft)
+		}
+	}
+	printReport(spaceID, cma.Environment, staleByOwner, archiveBefore)
+	if !*archive {
+		log.Printf("%d stale drafts are past their grace period, pass -archive to archive them", len(dueForArchiving))
+		return nil
+	}
+	failed := 0
+	for _, draft := range dueForArchiving {
+		err := cma.Entries.Archive(spaceID, draft)
+		if err != nil {
+			log.Printf("Entry %s could not be archived: %v", draft.Sys.ID, err)
+			failed++
+			continue
+		}
+		log.Printf("Entry %s was archived", draft.Sys.ID)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d stale drafts could not be archived", failed, len(dueForArchiving))
+	}
+	return nil
+}
+
+func printReport(spaceID, environment string, staleByOwner map[string][]*contentful.Entry, archiveBefore time.Time) {
+	owners := make([]string, 0, len(staleByOwner))
+	for owner := range staleByOwner {
+		owners = append(owners, owner)
+	}
+	sort.Strings(owners)
+	for _, owner := range owners {
+		fmt.Printf("Owner: %s\n", owner)
+		for _, draft := range staleByOwner[owner] {
+			marker := "   "
+			if updatedAt, err := time.Parse(time.RFC3339, draft.Sys.UpdatedAt); err == nil && updatedAt.Before(archiveBefore) {
+				marker = "(*)"
+			}
+			fmt.Printf("    %s %s %s updated %s https://app.contentful.com/spaces/%s/environments/%s/entries/%s\n",
+				marker, draft.Sys.ContentType.Sys.ID, draft.Sys.ID, draft.Sys.UpdatedAt, spaceID, environment, draft.Sys.ID)
+			if previewURL := common.PreviewURL(draft); previewURL != "" {
+				fmt.Printf("        preview: %s\n", previewURL)
+			}
+		}
+	}
+	fmt.Println("(*) grace period is over, the draft will be archived with -archive")
+}
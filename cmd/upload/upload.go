@@ -0,0 +1,80 @@
+package upload
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/foomo/contentful"
+
+	"github.com/foomo/contentfulcommander/cmd/common"
+	"github.com/foomo/contentfulcommander/contentfulclient"
+)
+
+const assetProcessingTimeout = 5 * time.Minute
+
+func Run(cma *contentful.Contentful, params []string) error {
+	flagSet := flag.NewFlagSet("upload", flag.ContinueOnError)
+	assetID := flagSet.String("id", "", "ID of the new asset, only with a single file")
+	locale := flagSet.String("locale", "", "locale of the title and file, defaults to the default locale")
+	title := flagSet.String("title", "", "title of the asset, defaults to the file name")
+	noPublish := flagSet.Bool("nopublish", false, "leave the new assets in draft")
+	err := flagSet.Parse(params)
+	if err != nil {
+		return err
+	}
+	if flagSet.NArg() < 2 {
+		return errors.New("upload needs a space and at least one file or URL")
+	}
+	sources := flagSet.Args()[1:]
+	if *assetID != "" && len(sources) > 1 {
+		return errors.New("an asset ID can only be given for a single file")
+	}
+	spaceID, environment := contentfulclient.GetSpaceAndEnvironment(flagSet.Arg(0))
+	cma.Environment = environment
+	ctx := context.Background()
+	operations := []contentfulclient.Operation{contentfulclient.OperationUpdate}
+	if !*noPublish {
+		operations = append(operations, contentfulclient.OperationPublish)
+	}
+	err = contentfulclient.Preflight(ctx, cma, spaceID, environment, operations...)
+	if err != nil {
+		return err
+	}
+	if *locale == "" {
+		*locale, err = contentfulclient.GetDefaultLocale(ctx, cma, spaceID)
+		if err != nil {
+			return err
+		}
+	}
+	failed := 0
+	for _, source := range sources {
+		assetTitle := *title
+		if assetTitle == "" {
+			assetTitle = strings.TrimSuffix(filepath.Base(source), filepath.Ext(source))
+		}
+		var asset *contentful.Asset
+		if strings.HasPrefix(source, "https://") || strings.HasPrefix(source, "http://") {
+			asset, err = common.CreateAssetFromURL(ctx, cma, spaceID, *assetID, *locale, assetTitle, source,
+				!*noPublish, assetProcessingTimeout)
+		} else {
+			asset, err = common.CreateAssetFromFile(ctx, cma, spaceID, *assetID, *locale, assetTitle, source,
+				!*noPublish, assetProcessingTimeout)
+		}
+		if err != nil {
+			log.Printf("Could not create an asset from %s: %v", source, err)
+			failed++
+			continue
+		}
+		fmt.Printf("%s %s\n", asset.Sys.ID, source)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d assets could not be created", failed, len(sources))
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package usage
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/foomo/contentful"
+
+	"github.com/foomo/contentfulcommander/contentfulclient"
+)
+
+type periodicUsage struct {
+	Metric    string `json:"metric"`
+	Usage     int    `json:"usage"`
+	DateRange struct {
+		StartAt string `json:"startAt"`
+		EndAt   string `json:"endAt"`
+	} `json:"dateRange"`
+}
+
+type periodicUsageCollection struct {
+	Items []periodicUsage `json:"items"`
+}
+
+func Run(cma *contentful.Contentful, params []string) error {
+	flagSet := flag.NewFlagSet("usage", flag.ContinueOnError)
+	recordLimit := flagSet.Int("recordlimit", 0, "record limit of the space plan, enables the headroom report")
+	organizationID := flagSet.String("org", "", "organization ID, enables the API usage report")
+	err := flagSet.Parse(params)
+	if err != nil {
+		return err
+	}
+	if flagSet.NArg() != 1 {
+		return errors.New("usage needs exactly one space parameter")
+	}
+	spaceID, environment := contentfulclient.GetSpaceAndEnvironment(flagSet.Arg(0))
+	cma.Environment = environment
+	err = contentfulclient.Preflight(context.Background(), cma, spaceID, environment, contentfulclient.OperationRead)
+	if err != nil {
+		return err
+	}
+
+	entries, err := getTotal(cma.Entries.List(spaceID))
+	if err != nil {
+		return err
+	}
+	assets, err := getTotal(cma.Assets.List(spaceID))
+	if err != nil {
+		return err
+	}
+	contentTypes, err := getTotal(cma.ContentTypes.List(spaceID))
+	if err != nil {
+		return err
+	}
+	locales, err := getTotal(cma.Locales.List(spaceID))
+	if err != nil {
+		return err
+	}
+	records := entries + assets
+	fmt.Printf("Space %s/%s\n", spaceID, environment)
+	fmt.Printf("    Entries:       %8d\n", entries)
+	fmt.Printf("    Assets:        %8d\n", assets)
+	if *recordLimit > 0 {
+		fmt.Printf("    Records:       %8d of %d (%d left, %.1f%% used)\n",
+			records, *recordLimit, *recordLimit-records, float64(records)*100/float64(*recordLimit))
+	} else {
+		fmt.Printf("    Records:       %8d\n", records)
+	}
+	fmt.Printf("    Content types: %8d\n", contentTypes)
+	fmt.Printf("    Locales:       %8d\n", locales)
+
+	if *organizationID == "" {
+		return nil
+	}
+	usages, err := getSpacePeriodicUsages(cma, *organizationID, spaceID)
+	if err != nil {
+		return fmt.Errorf("could not get API usage for organization %s: %v", *organizationID, err)
+	}
+	fmt.Printf("API usage of space %s in the current period\n", spaceID)
+	for _, usage := range usages {
+		fmt.Printf("    %-4s %12d requests from %s to %s\n", usage.Metric, usage.Usage, usage.DateRange.StartAt, usage.DateRange.EndAt)
+	}
+	return nil
+}
+
+func getTotal(col *contentful.Collection) (int, error) {
+	col.Query.Limit(1)
+	col, err := col.Get()
+	if err != nil {
+		return 0, err
+	}
+	return col.Total, nil
+}
+
+func getSpacePeriodicUsages(cma *contentful.Contentful, organizationID, spaceID string) ([]periodicUsage, error) {
+	query := url.Values{}
+	query.Set("metric[in]", "cma,cda,cpa,gql")
+	query.Set("space.sys.id[in]", spaceID)
+	// the usage endpoints are only available with this alpha feature header
+	headers := map[string]string{"X-Contentful-Enable-Alpha-Feature": "usage-insights"}
+	var usages periodicUsageCollection
+	err := contentfulclient.DoWithHeaders(context.Background(), cma, http.MethodGet,
+		fmt.Sprintf("/organizations/%s/space_periodic_usages?%s", organizationID, query.Encode()), headers, nil, &usages)
+	if err != nil {
+		return nil, err
+	}
+	return usages.Items, nil
+}
@@ -0,0 +1,155 @@
+package xliff
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/foomo/contentful"
+
+	"github.com/foomo/contentfulcommander/cmd/common"
+	"github.com/foomo/contentfulcommander/contentfulclient"
+)
+
+func runExport(cma *contentful.Contentful, params []string) error {
+	flagSet := flag.NewFlagSet("xliff export", flag.ContinueOnError)
+	version := flagSet.String("version", Version12, "XLIFF version to write, 1.2 or 2.0")
+	fieldList := flagSet.String("fields", "", "comma separated list of field IDs to export, defaults to all localized text fields")
+	sinceParam := flagSet.String("since", "", "only export entries updated after this date or RFC 3339 time")
+	err := flagSet.Parse(params)
+	if err != nil {
+		return err
+	}
+	if flagSet.NArg() != 5 {
+		return errors.New("xliff export needs space, contenttype, sourcelocale, targetlocale and file")
+	}
+	args := flagSet.Args()
+	spaceID, environment := contentfulclient.GetSpaceAndEnvironment(args[0])
+	cma.Environment = environment
+	contentTypeID := args[1]
+	sourceLocale := args[2]
+	targetLocale := args[3]
+	fileName := args[4]
+	var since time.Time
+	if *sinceParam != "" {
+		since, err = parseSince(*sinceParam)
+		if err != nil {
+			return err
+		}
+	}
+	err = contentfulclient.Preflight(context.Background(), cma, spaceID, environment, contentfulclient.OperationRead)
+	if err != nil {
+		return err
+	}
+
+	contentType, err := cma.ContentTypes.Get(spaceID, contentTypeID)
+	if err != nil {
+		return fmt.Errorf("could not get content type %s: %v", contentTypeID, err)
+	}
+	fields, err := selectFields(contentType, *fieldList)
+	if err != nil {
+		return err
+	}
+	var entries []*contentful.Entry
+	if since.IsZero() {
+		entries, err = common.GetEntriesByContentType(cma, spaceID, contentTypeID)
+	} else {
+		entries, err = common.GetEntriesModifiedSince(cma, spaceID, contentTypeID, since)
+	}
+	if err != nil {
+		return err
+	}
+	doc := document{SourceLocale: sourceLocale, TargetLocale: targetLocale}
+	for _, entry := range entries {
+		entrySegs := entrySegments{EntryID: entry.Sys.ID}
+		for _, field := range fields {
+			localizedValue, ok := entry.Fields[field.ID].(map[string]any)
+			if !ok {
+				continue
+			}
+			entrySegs.Segments = append(entrySegs.Segments,
+				fieldSegments(field, localizedValue[sourceLocale], localizedValue[targetLocale])...)
+		}
+		if len(entrySegs.Segments) > 0 {
+			doc.Entries = append(doc.Entries, entrySegs)
+		}
+	}
+	out, err := marshalDocument(doc, *version)
+	if err != nil {
+		return err
+	}
+	err = os.WriteFile(fileName, out, 0o600)
+	if err != nil {
+		return err
+	}
+	log.Printf("Exported %d of %d entries to %s", len(doc.Entries), len(entries), fileName)
+	return nil
+}
+
+func selectFields(contentType *contentful.ContentType, fieldList string) ([]*contentful.Field, error) {
+	wanted := map[string]bool{}
+	for _, fieldID := range strings.Split(fieldList, ",") {
+		if fieldID != "" {
+			wanted[fieldID] = true
+		}
+	}
+	var fields []*contentful.Field
+	for _, field := range contentType.Fields {
+		if !field.Localized || field.Omitted || !isTextField(field.Type) {
+			continue
+		}
+		if len(wanted) > 0 && !wanted[field.ID] {
+			continue
+		}
+		fields = append(fields, field)
+		delete(wanted, field.ID)
+	}
+	for fieldID := range wanted {
+		return nil, fmt.Errorf("field %s is not a localized text field of content type %s", fieldID, contentType.Sys.ID)
+	}
+	return fields, nil
+}
+
+func isTextField(fieldType string) bool {
+	return fieldType == contentful.FieldTypeSymbol || fieldType == contentful.FieldTypeText || fieldType == fieldTypeRichText
+}
+
+func fieldSegments(field *contentful.Field, source, target any) (segments []segment) {
+	if field.Type != fieldTypeRichText {
+		sourceText, _ := source.(string)
+		if strings.TrimSpace(sourceText) == "" {
+			return nil
+		}
+		targetText, _ := target.(string)
+		return []segment{{ID: field.ID, Source: sourceText, Target: targetText}}
+	}
+	walkTextNodes(source, nil, func(path []int, textNode map[string]any) {
+		sourceText, _ := textNode["value"].(string)
+		if strings.TrimSpace(sourceText) == "" {
+			return
+		}
+		seg := segment{ID: segmentID(field.ID, path), Source: sourceText}
+		if targetNode := getTextNode(target, path); targetNode != nil {
+			seg.Target, _ = targetNode["value"].(string)
+		}
+		segments = append(segments, seg)
+	})
+	return segments
+}
+
+func parseSince(value string) (time.Time, error) {
+	since, err := time.Parse(time.RFC3339, value)
+	if err == nil {
+		return since, nil
+	}
+	since, err = time.ParseInLocation("2006-01-02", value, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("since must be a date like 2006-01-02 or an RFC 3339 time: %v", err)
+	}
+	return since, nil
+}
@@ -0,0 +1,161 @@
+package xliff
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/foomo/contentful"
+
+	"github.com/foomo/contentfulcommander/cmd/common"
+	"github.com/foomo/contentfulcommander/contentfulclient"
+)
+
+func runImport(cma *contentful.Contentful, params []string) error {
+	if len(params) != 2 {
+		return errors.New("xliff import needs space and file")
+	}
+	spaceID, environment := contentfulclient.GetSpaceAndEnvironment(params[0])
+	cma.Environment = environment
+	err := contentfulclient.Preflight(context.Background(), cma, spaceID, environment,
+		contentfulclient.OperationUpdate, contentfulclient.OperationPublish)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(params[1])
+	if err != nil {
+		return err
+	}
+	doc, err := unmarshalDocument(data)
+	if err != nil {
+		return fmt.Errorf("could not parse %s: %v", params[1], err)
+	}
+	log.Printf("Importing %d entries from %s to %s", len(doc.Entries), doc.SourceLocale, doc.TargetLocale)
+	contentTypes := map[string]*contentful.ContentType{}
+	failed := 0
+	for _, entrySegs := range doc.Entries {
+		err := importEntry(cma, spaceID, doc, entrySegs, contentTypes)
+		if err != nil {
+			log.Printf("Entry %s could not be imported: %v", entrySegs.EntryID, err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d entries could not be imported", failed, len(doc.Entries))
+	}
+	log.Print("All done.")
+	return nil
+}
+
+func importEntry(cma *contentful.Contentful, spaceID string, doc document, entrySegs entrySegments,
+	contentTypes map[string]*contentful.ContentType,
+) error {
+	entry, err := cma.Entries.Get(spaceID, entrySegs.EntryID)
+	if err != nil {
+		return err
+	}
+	if entry == nil {
+		return errors.New("entry not found")
+	}
+	contentTypeID := entry.Sys.ContentType.Sys.ID
+	contentType, ok := contentTypes[contentTypeID]
+	if !ok {
+		contentType, err = cma.ContentTypes.Get(spaceID, contentTypeID)
+		if err != nil {
+			return err
+		}
+		contentTypes[contentTypeID] = contentType
+	}
+	fieldTypes := map[string]string{}
+	for _, field := range contentType.Fields {
+		if field.Localized {
+			fieldTypes[field.ID] = field.Type
+		}
+	}
+	// RichText segments are applied per field once the document to write them to is known
+	richTextSegments := map[string][]segment{}
+	changed := false
+	for _, seg := range entrySegs.Segments {
+		if seg.Target == "" {
+			continue
+		}
+		fieldID, _, err := parseSegmentID(seg.ID)
+		if err != nil {
+			return err
+		}
+		fieldType, ok := fieldTypes[fieldID]
+		if !ok {
+			return fmt.Errorf("field %s is not a localized field of content type %s", fieldID, contentTypeID)
+		}
+		localizedValue, ok := entry.Fields[fieldID].(map[string]any)
+		if !ok {
+			return fmt.Errorf("field %s has no value to translate", fieldID)
+		}
+		if fieldType != fieldTypeRichText {
+			localizedValue[doc.TargetLocale] = seg.Target
+			changed = true
+			continue
+		}
+		richTextSegments[fieldID] = append(richTextSegments[fieldID], seg)
+	}
+	for fieldID, segments := range richTextSegments {
+		localizedValue := entry.Fields[fieldID].(map[string]any)
+		targetDocument, err := getRichTextTarget(fieldID, localizedValue[doc.SourceLocale], localizedValue[doc.TargetLocale], segments)
+		if err != nil {
+			return err
+		}
+		for _, seg := range segments {
+			_, path, _ := parseSegmentID(seg.ID)
+			textNode := getTextNode(targetDocument, path)
+			if textNode == nil {
+				return fmt.Errorf("segment %s does not match the source document anymore", seg.ID)
+			}
+			textNode["value"] = seg.Target
+		}
+		localizedValue[doc.TargetLocale] = targetDocument
+		changed = true
+	}
+	if !changed {
+		log.Printf("Entry %s has no translated segments", entry.Sys.ID)
+		return nil
+	}
+	return common.SmartUpdateEntry(entry, nil, cma, spaceID)
+}
+
+// getRichTextTarget returns a copy of the document the translated segments of a RichText field are written
+// to. An existing translation with the text nodes of the source document is used, so that text nodes
+// without a segment keep their translation. Otherwise the source document is copied, which is only allowed
+// if the segments translate all of its text nodes, as the others would stay in the source language.
+func getRichTextTarget(fieldID string, source, target any, segments []segment) (any, error) {
+	if target != nil && getTextNodePaths(source) == getTextNodePaths(target) {
+		return deepCopy(target)
+	}
+	translated := make(map[string]bool, len(segments))
+	for _, seg := range segments {
+		translated[seg.ID] = true
+	}
+	missing := 0
+	walkTextNodes(source, nil, func(path []int, textNode map[string]any) {
+		sourceText, _ := textNode["value"].(string)
+		if strings.TrimSpace(sourceText) != "" && !translated[segmentID(fieldID, path)] {
+			missing++
+		}
+	})
+	if missing > 0 {
+		return nil, fmt.Errorf("field %s has no translation with the structure of the source and the file leaves %d of its text nodes untranslated",
+			fieldID, missing)
+	}
+	return deepCopy(source)
+}
+
+// getTextNodePaths returns the paths of all text nodes of a RichText document as one comparable string
+func getTextNodePaths(document any) string {
+	var paths []string
+	walkTextNodes(document, nil, func(path []int, _ map[string]any) {
+		paths = append(paths, segmentID("", path))
+	})
+	return strings.Join(paths, " ")
+}
@@ -0,0 +1,248 @@
+package xliff
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/foomo/contentful"
+)
+
+const (
+	Version12 = "1.2"
+	Version20 = "2.0"
+
+	fieldTypeRichText = "RichText"
+	pathSeparator     = "."
+)
+
+type segment struct {
+	ID     string
+	Source string
+	Target string
+}
+
+type entrySegments struct {
+	EntryID  string
+	Segments []segment
+}
+
+type document struct {
+	SourceLocale string
+	TargetLocale string
+	Entries      []entrySegments
+}
+
+type xliff12 struct {
+	XMLName xml.Name      `xml:"urn:oasis:names:tc:xliff:document:1.2 xliff"`
+	Version string        `xml:"version,attr"`
+	Files   []xliff12File `xml:"file"`
+}
+
+type xliff12File struct {
+	Original       string        `xml:"original,attr"`
+	SourceLanguage string        `xml:"source-language,attr"`
+	TargetLanguage string        `xml:"target-language,attr"`
+	Datatype       string        `xml:"datatype,attr"`
+	Units          []xliff12Unit `xml:"body>trans-unit"`
+}
+
+type xliff12Unit struct {
+	ID     string `xml:"id,attr"`
+	Source string `xml:"source"`
+	Target string `xml:"target,omitempty"`
+}
+
+type xliff20 struct {
+	XMLName xml.Name      `xml:"urn:oasis:names:tc:xliff:document:2.0 xliff"`
+	Version string        `xml:"version,attr"`
+	SrcLang string        `xml:"srcLang,attr"`
+	TrgLang string        `xml:"trgLang,attr"`
+	Files   []xliff20File `xml:"file"`
+}
+
+type xliff20File struct {
+	ID    string        `xml:"id,attr"`
+	Units []xliff20Unit `xml:"unit"`
+}
+
+type xliff20Unit struct {
+	ID     string `xml:"id,attr"`
+	Source string `xml:"segment>source"`
+	Target string `xml:"segment>target,omitempty"`
+}
+
+func Run(cma *contentful.Contentful, params []string) error {
+	switch params[0] {
+	case "export":
+		return runExport(cma, params[1:])
+	case "import":
+		return runImport(cma, params[1:])
+	default:
+		return fmt.Errorf("unknown xliff subcommand %q, use export or import", params[0])
+	}
+}
+
+func marshalDocument(doc document, version string) ([]byte, error) {
+	var v any
+	switch version {
+	case Version12:
+		x := xliff12{Version: Version12}
+		for _, entry := range doc.Entries {
+			file := xliff12File{
+				Original:       entry.EntryID,
+				SourceLanguage: doc.SourceLocale,
+				TargetLanguage: doc.TargetLocale,
+				Datatype:       "plaintext",
+			}
+			for _, seg := range entry.Segments {
+				file.Units = append(file.Units, xliff12Unit(seg))
+			}
+			x.Files = append(x.Files, file)
+		}
+		v = x
+	case Version20:
+		x := xliff20{Version: Version20, SrcLang: doc.SourceLocale, TrgLang: doc.TargetLocale}
+		for _, entry := range doc.Entries {
+			file := xliff20File{ID: entry.EntryID}
+			for _, seg := range entry.Segments {
+				file.Units = append(file.Units, xliff20Unit(seg))
+			}
+			x.Files = append(x.Files, file)
+		}
+		v = x
+	default:
+		return nil, fmt.Errorf("unsupported XLIFF version %q", version)
+	}
+	out, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), out...), nil
+}
+
+func unmarshalDocument(data []byte) (doc document, err error) {
+	var probe struct {
+		Version string `xml:"version,attr"`
+	}
+	err = xml.Unmarshal(data, &probe)
+	if err != nil {
+		return doc, err
+	}
+	switch probe.Version {
+	case Version12:
+		var x xliff12
+		err = xml.Unmarshal(data, &x)
+		if err != nil {
+			return doc, err
+		}
+		for _, file := range x.Files {
+			if doc.SourceLocale == "" {
+				doc.SourceLocale = file.SourceLanguage
+				doc.TargetLocale = file.TargetLanguage
+			}
+			if file.SourceLanguage != doc.SourceLocale || file.TargetLanguage != doc.TargetLocale {
+				return doc, errors.New("all files in the XLIFF document must share the same language pair")
+			}
+			entry := entrySegments{EntryID: file.Original}
+			for _, unit := range file.Units {
+				entry.Segments = append(entry.Segments, segment(unit))
+			}
+			doc.Entries = append(doc.Entries, entry)
+		}
+	case Version20:
+		var x xliff20
+		err = xml.Unmarshal(data, &x)
+		if err != nil {
+			return doc, err
+		}
+		doc.SourceLocale = x.SrcLang
+		doc.TargetLocale = x.TrgLang
+		for _, file := range x.Files {
+			entry := entrySegments{EntryID: file.ID}
+			for _, unit := range file.Units {
+				entry.Segments = append(entry.Segments, segment(unit))
+			}
+			doc.Entries = append(doc.Entries, entry)
+		}
+	default:
+		return doc, fmt.Errorf("unsupported XLIFF version %q", probe.Version)
+	}
+	if doc.SourceLocale == "" || doc.TargetLocale == "" {
+		return doc, errors.New("XLIFF document does not declare source and target languages")
+	}
+	return doc, nil
+}
+
+// walkTextNodes calls fn for every text node of a RichText document with the
+// path of content indexes leading to it
+func walkTextNodes(node any, path []int, fn func(path []int, textNode map[string]any)) {
+	nodeMap, ok := node.(map[string]any)
+	if !ok {
+		return
+	}
+	if nodeMap["nodeType"] == "text" {
+		fn(path, nodeMap)
+		return
+	}
+	content, ok := nodeMap["content"].([]any)
+	if !ok {
+		return
+	}
+	for i, child := range content {
+		childPath := make([]int, len(path), len(path)+1)
+		copy(childPath, path)
+		walkTextNodes(child, append(childPath, i), fn)
+	}
+}
+
+func getTextNode(node any, path []int) map[string]any {
+	for _, index := range path {
+		nodeMap, ok := node.(map[string]any)
+		if !ok {
+			return nil
+		}
+		content, ok := nodeMap["content"].([]any)
+		if !ok || index >= len(content) {
+			return nil
+		}
+		node = content[index]
+	}
+	nodeMap, ok := node.(map[string]any)
+	if !ok || nodeMap["nodeType"] != "text" {
+		return nil
+	}
+	return nodeMap
+}
+
+func segmentID(fieldID string, path []int) string {
+	parts := []string{fieldID}
+	for _, index := range path {
+		parts = append(parts, strconv.Itoa(index))
+	}
+	return strings.Join(parts, pathSeparator)
+}
+
+func parseSegmentID(id string) (fieldID string, path []int, err error) {
+	parts := strings.Split(id, pathSeparator)
+	for _, part := range parts[1:] {
+		index, errAtoi := strconv.Atoi(part)
+		if errAtoi != nil {
+			return "", nil, fmt.Errorf("invalid segment id %q", id)
+		}
+		path = append(path, index)
+	}
+	return parts[0], path, nil
+}
+
+func deepCopy(value any) (copied any, err error) {
+	byt, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(byt, &copied)
+	return copied, err
+}
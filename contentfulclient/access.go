@@ -0,0 +1,106 @@
+package contentfulclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/foomo/contentful"
+
+	"github.com/foomo/contentfulcommander/model"
+)
+
+// The API key and role endpoints live on the space and not on environments, which is why they are
+// not handled with the APIKeys service of the contentful package
+
+type APIKey struct {
+	Sys           *model.ContentfulSys `json:"sys,omitempty"`
+	Name          string               `json:"name"`
+	Description   string               `json:"description,omitempty"`
+	AccessToken   string               `json:"accessToken,omitempty"`
+	Environments  []model.ReferenceSys `json:"environments,omitempty"`
+	PreviewAPIKey *model.ReferenceSys  `json:"preview_api_key,omitempty"`
+}
+
+type Role struct {
+	Sys         *model.ContentfulSys `json:"sys,omitempty"`
+	Name        string               `json:"name"`
+	Description string               `json:"description,omitempty"`
+	Policies    []json.RawMessage    `json:"policies"`
+	Permissions json.RawMessage      `json:"permissions"`
+}
+
+func EnvironmentLinks(environments []string) []model.ReferenceSys {
+	links := make([]model.ReferenceSys, 0, len(environments))
+	for _, environment := range environments {
+		links = append(links, model.ReferenceSys{
+			Sys: model.ReferenceSysAttributes{
+				ID:       environment,
+				Type:     "Link",
+				LinkType: "Environment",
+			},
+		})
+	}
+	return links
+}
+
+func ListAPIKeys(ctx context.Context, cma *contentful.Contentful, spaceID string) ([]APIKey, error) {
+	return listAll[APIKey](ctx, cma, fmt.Sprintf("/spaces/%s/api_keys", spaceID))
+}
+
+// UpsertAPIKey creates the delivery API key if it has no sys and updates it otherwise
+func UpsertAPIKey(ctx context.Context, cma *contentful.Contentful, spaceID string, apiKey *APIKey) error {
+	payload := APIKey{
+		Name:         apiKey.Name,
+		Description:  apiKey.Description,
+		Environments: apiKey.Environments,
+	}
+	if apiKey.Sys == nil {
+		return Do(ctx, cma, http.MethodPost, fmt.Sprintf("/spaces/%s/api_keys", spaceID), payload, apiKey)
+	}
+	return DoWithHeaders(ctx, cma, http.MethodPut, fmt.Sprintf("/spaces/%s/api_keys/%s", spaceID, apiKey.Sys.ID),
+		versionHeader(apiKey.Sys.Version), payload, apiKey)
+}
+
+func GetAPIKey(ctx context.Context, cma *contentful.Contentful, spaceID, apiKeyID string) (*APIKey, error) {
+	var apiKey APIKey
+	err := Do(ctx, cma, http.MethodGet, fmt.Sprintf("/spaces/%s/api_keys/%s", spaceID, apiKeyID), nil, &apiKey)
+	if err != nil {
+		return nil, err
+	}
+	return &apiKey, nil
+}
+
+func ListRoles(ctx context.Context, cma *contentful.Contentful, spaceID string) ([]Role, error) {
+	return listAll[Role](ctx, cma, fmt.Sprintf("/spaces/%s/roles", spaceID))
+}
+
+func GetRole(ctx context.Context, cma *contentful.Contentful, spaceID, roleID string) (*Role, error) {
+	var role Role
+	err := Do(ctx, cma, http.MethodGet, fmt.Sprintf("/spaces/%s/roles/%s", spaceID, roleID), nil, &role)
+	if err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+// UpsertRole creates the role if it has no sys and updates it otherwise
+func UpsertRole(ctx context.Context, cma *contentful.Contentful, spaceID string, role *Role) error {
+	payload := Role{
+		Name:        role.Name,
+		Description: role.Description,
+		Policies:    role.Policies,
+		Permissions: role.Permissions,
+	}
+	if role.Sys == nil {
+		return Do(ctx, cma, http.MethodPost, fmt.Sprintf("/spaces/%s/roles", spaceID), payload, role)
+	}
+	return DoWithHeaders(ctx, cma, http.MethodPut, fmt.Sprintf("/spaces/%s/roles/%s", spaceID, role.Sys.ID),
+		versionHeader(role.Sys.Version), payload, role)
+}
+
+func versionHeader(version float64) map[string]string {
+	return map[string]string{"X-Contentful-Version": strconv.Itoa(int(version))}
+}
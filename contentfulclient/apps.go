@@ -0,0 +1,73 @@
+package contentfulclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/foomo/contentful"
+
+	"github.com/foomo/contentfulcommander/model"
+)
+
+// App definitions belong to the organization and are installed per environment, the contentful package
+// has no services for either of them
+
+type AppDefinition struct {
+	Sys       *model.ContentfulSys `json:"sys,omitempty"`
+	Name      string               `json:"name"`
+	SrcURL    string               `json:"src,omitempty"`
+	Locations []json.RawMessage    `json:"locations,omitempty"`
+}
+
+type AppInstallation struct {
+	Sys struct {
+		AppDefinition model.ReferenceSys `json:"appDefinition"`
+		CreatedAt     string             `json:"createdAt,omitempty"`
+		UpdatedAt     string             `json:"updatedAt,omitempty"`
+	} `json:"sys"`
+	Parameters json.RawMessage `json:"parameters,omitempty"`
+}
+
+// GetOrganizationID returns the ID of the organization a space belongs to
+func GetOrganizationID(ctx context.Context, cma *contentful.Contentful, spaceID string) (string, error) {
+	var space struct {
+		Sys struct {
+			Organization model.ReferenceSys `json:"organization"`
+		} `json:"sys"`
+	}
+	err := Do(ctx, cma, http.MethodGet, "/spaces/"+spaceID, nil, &space)
+	return space.Sys.Organization.Sys.ID, err
+}
+
+func ListAppDefinitions(ctx context.Context, cma *contentful.Contentful, organizationID string) ([]AppDefinition, error) {
+	return listAll[AppDefinition](ctx, cma, fmt.Sprintf("/organizations/%s/app_definitions", organizationID))
+}
+
+func ListAppInstallations(ctx context.Context, cma *contentful.Contentful, spaceID, environment string) ([]AppInstallation, error) {
+	return listAll[AppInstallation](ctx, cma, fmt.Sprintf("/spaces/%s/environments/%s/app_installations", spaceID, environment))
+}
+
+// InstallApp installs an app in an environment, or replaces the parameters if it is already installed
+func InstallApp(ctx context.Context, cma *contentful.Contentful, spaceID, environment, appDefinitionID string,
+	parameters json.RawMessage,
+) (*AppInstallation, error) {
+	payload := map[string]any{}
+	if len(parameters) > 0 {
+		payload["parameters"] = parameters
+	}
+	var installation AppInstallation
+	err := Do(ctx, cma, http.MethodPut,
+		fmt.Sprintf("/spaces/%s/environments/%s/app_installations/%s", spaceID, environment, appDefinitionID),
+		payload, &installation)
+	if err != nil {
+		return nil, err
+	}
+	return &installation, nil
+}
+
+func UninstallApp(ctx context.Context, cma *contentful.Contentful, spaceID, environment, appDefinitionID string) error {
+	return Do(ctx, cma, http.MethodDelete,
+		fmt.Sprintf("/spaces/%s/environments/%s/app_installations/%s", spaceID, environment, appDefinitionID), nil, nil)
+}
@@ -0,0 +1,128 @@
+package contentfulclient
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/foomo/contentful"
+)
+
+const (
+	// BulkActionMaxItems is the maximum number of entities per bulk action
+	BulkActionMaxItems      = 200
+	bulkActionPollInterval  = time.Second
+	bulkActionStatusSuccess = "succeeded"
+	bulkActionStatusFailed  = "failed"
+)
+
+// BulkLink references an entry or asset in a bulk action, the version is required for publishing
+type BulkLink struct {
+	ID       string
+	LinkType string
+	Version  int
+}
+
+type bulkActionLinkSys struct {
+	Type     string `json:"type"`
+	LinkType string `json:"linkType"`
+	ID       string `json:"id"`
+	Version  int    `json:"version,omitempty"`
+}
+
+type bulkActionPayload struct {
+	Entities struct {
+		Sys struct {
+			Type string `json:"type"`
+		} `json:"sys"`
+		Items []struct {
+			Sys bulkActionLinkSys `json:"sys"`
+		} `json:"items"`
+	} `json:"entities"`
+}
+
+type bulkAction struct {
+	Sys struct {
+		ID     string `json:"id"`
+		Status string `json:"status"`
+	} `json:"sys"`
+	Error *struct {
+		Sys struct {
+			ID string `json:"id"`
+		} `json:"sys"`
+		Message string `json:"message"`
+		Details any    `json:"details"`
+	} `json:"error"`
+}
+
+// BulkPublish publishes the entities with bulk actions of up to BulkActionMaxItems entities each and waits
+// for every action to finish before starting the next one, so the order of the links is kept across actions
+func BulkPublish(ctx context.Context, cma *contentful.Contentful, spaceID string, links []BulkLink) error {
+	return runBulkActions(ctx, cma, spaceID, "publish", links)
+}
+
+// BulkUnpublish works like BulkPublish for unpublishing, the versions of the links are not needed
+func BulkUnpublish(ctx context.Context, cma *contentful.Contentful, spaceID string, links []BulkLink) error {
+	unversioned := make([]BulkLink, len(links))
+	for i, link := range links {
+		unversioned[i] = BulkLink{ID: link.ID, LinkType: link.LinkType}
+	}
+	return runBulkActions(ctx, cma, spaceID, "unpublish", unversioned)
+}
+
+func runBulkActions(ctx context.Context, cma *contentful.Contentful, spaceID, action string, links []BulkLink) error {
+	basePath := fmt.Sprintf("/spaces/%s/environments/%s/bulk_actions", spaceID, cma.Environment)
+	for start := 0; start < len(links); start += BulkActionMaxItems {
+		end := start + BulkActionMaxItems
+		if end > len(links) {
+			end = len(links)
+		}
+		var payload bulkActionPayload
+		payload.Entities.Sys.Type = "Array"
+		for _, link := range links[start:end] {
+			item := struct {
+				Sys bulkActionLinkSys `json:"sys"`
+			}{Sys: bulkActionLinkSys{Type: "Link", LinkType: link.LinkType, ID: link.ID, Version: link.Version}}
+			payload.Entities.Items = append(payload.Entities.Items, item)
+		}
+		var created bulkAction
+		err := Do(ctx, cma, http.MethodPost, basePath+"/"+action, payload, &created)
+		if err != nil {
+			return fmt.Errorf("could not start bulk %s of %d entities: %v", action, end-start, err)
+		}
+		log.Printf("Started bulk %s %s of entities %d to %d of %d", action, created.Sys.ID, start+1, end, len(links))
+		err = waitForBulkAction(ctx, cma, basePath+"/actions/"+created.Sys.ID)
+		if err != nil {
+			return fmt.Errorf("bulk %s %s: %v", action, created.Sys.ID, err)
+		}
+	}
+	return nil
+}
+
+func waitForBulkAction(ctx context.Context, cma *contentful.Contentful, path string) error {
+	ticker := time.NewTicker(bulkActionPollInterval)
+	defer ticker.Stop()
+	for {
+		var current bulkAction
+		err := Do(ctx, cma, http.MethodGet, path, nil, &current)
+		if err != nil {
+			return err
+		}
+		switch current.Sys.Status {
+		case bulkActionStatusSuccess:
+			return nil
+		case bulkActionStatusFailed:
+			if current.Error == nil {
+				return fmt.Errorf("failed without details")
+			}
+			return fmt.Errorf("failed with %s: %s %v", current.Error.Sys.ID, current.Error.Message, current.Error.Details)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
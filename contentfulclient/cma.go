@@ -2,6 +2,8 @@ package contentfulclient
 
 import (
 	"encoding/json"
+	"fmt"
+	"log"
 	"os"
 	"os/user"
 	"strings"
@@ -9,6 +11,57 @@ import (
 	"github.com/foomo/contentful"
 )
 
+const defaultEnvironment = "master"
+
+// Config holds the environment and connection settings shared by all commands
+type Config struct {
+	// DefaultEnvironment is used for space parameters without an environment
+	DefaultEnvironment string
+	// ProtectedEnvironments can only be changed by mutating commands if Force is set
+	ProtectedEnvironments []string
+	Force                 bool
+	// Proxy is the URL of an HTTP proxy, if empty the proxy environment variables are used
+	Proxy string
+	// CACertFile is a PEM file with certificates to trust in addition to the system ones
+	CACertFile string
+	// UserAgent identifies the application in the requests to Contentful
+	UserAgent string
+	// Debug enables tracing of all requests, see newTracingTransport
+	Debug string
+	// StrictDecode makes loading fail on items that cannot be decoded instead of skipping them
+	StrictDecode bool
+	// RateLimits are the maximum requests per second for each request class, missing or zero means unlimited
+	RateLimits map[RequestClass]float64
+	// Retry is applied to all requests, see retryTransport
+	Retry RetryPolicy
+	// PublishBlackout is the daily window in which publishing to protected environments is refused
+	PublishBlackout *PublishBlackout
+	// Lock makes mutating commands lock the environment they change, see acquireLock
+	Lock bool
+	// History makes mutating commands record their runs in the environments they change, see RecordRun
+	History bool
+	// Concurrency is the number of pages loaded at the same time by GetAll
+	Concurrency int
+}
+
+var config = Config{
+	DefaultEnvironment:    defaultEnvironment,
+	ProtectedEnvironments: []string{defaultEnvironment},
+}
+
+func Configure(c Config) error {
+	if c.DefaultEnvironment == "" {
+		c.DefaultEnvironment = defaultEnvironment
+	}
+	client, err := newHTTPClient(c)
+	if err != nil {
+		return err
+	}
+	config = c
+	httpClient = client
+	return nil
+}
+
 type contentfulRc struct {
 	ManagementToken string `json:"managementToken"`
 }
@@ -31,7 +84,13 @@ func GetCmaKeyFromRcFile() string {
 }
 
 func GetCMA(cmaKey string) *contentful.Contentful {
-	return contentful.NewCMA(cmaKey)
+	cma := contentful.NewCMA(cmaKey)
+	cma.SetHTTPClient(httpClient)
+	if config.UserAgent != "" {
+		cma.Headers["User-Agent"] = config.UserAgent
+		cma.Headers["X-Contentful-User-Agent"] = fmt.Sprintf("app %s; %s", config.UserAgent, cma.Headers["X-Contentful-User-Agent"])
+	}
+	return cma
 }
 
 func GetSpaceAndEnvironment(param string) (spaceID string, environment string) {
@@ -39,5 +98,23 @@ func GetSpaceAndEnvironment(param string) (spaceID string, environment string) {
 	if len(splits) > 1 {
 		return splits[0], splits[1]
 	}
-	return splits[0], "master"
+	log.Printf("No environment given for space %s, using %s", splits[0], config.DefaultEnvironment)
+	return splits[0], config.DefaultEnvironment
+}
+
+// CheckMutable returns an error if the environment is protected and the command was not forced
+func CheckMutable(spaceID, environment string) error {
+	if config.Force || !isProtected(environment) {
+		return nil
+	}
+	return fmt.Errorf("environment %s of space %s is protected, pass -force to change it", environment, spaceID)
+}
+
+func isProtected(environment string) bool {
+	for _, protected := range config.ProtectedEnvironments {
+		if protected == environment {
+			return true
+		}
+	}
+	return false
 }
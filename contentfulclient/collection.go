@@ -0,0 +1,177 @@
+package contentfulclient
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/foomo/contentful"
+)
+
+// GetAll loads all items of the collection returned by newCollection and verifies that the number
+// of loaded items matches the total reported by the API. The paging of contentful.Collection.GetAll
+// stops at the first short page and can come back with partial data, in that case the collection
+// is loaded again page by page with an explicit skip and limit. With a concurrency greater than one
+// the pages are loaded concurrently right away.
+func GetAll(newCollection func() *contentful.Collection) (*contentful.Collection, error) {
+	if config.Concurrency > 1 {
+		return getAllConcurrent(newCollection, config.Concurrency)
+	}
+	col, err := newCollection().GetAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(col.Items) == col.Total {
+		return col, nil
+	}
+	log.Printf("Loaded %d of %d items, falling back to paging with skip and limit", len(col.Items), col.Total)
+	return getAllPaged(newCollection)
+}
+
+func getAllPaged(newCollection func() *contentful.Collection) (*contentful.Collection, error) {
+	var (
+		items []any
+		last  *contentful.Collection
+	)
+	err := GetPages(newCollection, func(page *contentful.Collection) error {
+		items = append(items, page.Items...)
+		last = page
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(items) != last.Total {
+		return nil, fmt.Errorf("loaded %d of %d items", len(items), last.Total)
+	}
+	last.Items = items
+	return last, nil
+}
+
+// GetPages loads the collection returned by newCollection page by page with an explicit skip and limit
+// and calls fn for every page, so that large collections can be processed without keeping all items in
+// memory. The items are ordered by sys.id to keep them from shifting between pages. The page is reused
+// for the next request, fn must copy the items it wants to keep.
+func GetPages(newCollection func() *contentful.Collection, fn func(page *contentful.Collection) error) error {
+	col := newCollection()
+	col.Query.Order("sys.id", false)
+	col.Query.Limit(col.Limit)
+	loaded := 0
+	for {
+		// the skip of contentful.Query is an uint16, as a plain parameter it also works beyond 65535 items
+		col.Query.Equal("skip", loaded)
+		col.Items = nil
+		page, err := col.Get()
+		if err != nil {
+			return err
+		}
+		loaded += len(page.Items)
+		if page.Total > int(col.Limit) {
+			log.Printf("Loaded %d of %d items", loaded, page.Total)
+		}
+		err = fn(page)
+		if err != nil {
+			return err
+		}
+		if len(page.Items) == 0 || loaded >= page.Total {
+			return nil
+		}
+	}
+}
+
+// getAllConcurrent loads the first page to learn the total and then the remaining pages with up to
+// concurrency requests at the same time. The rate limits of the transport apply to every request.
+func getAllConcurrent(newCollection func() *contentful.Collection, concurrency int) (*contentful.Collection, error) {
+	start := time.Now()
+	first, err := getPage(newCollection, 0)
+	if err != nil {
+		return nil, err
+	}
+	limit := int(first.Limit)
+	if limit == 0 || first.Total <= len(first.Items) {
+		return first, nil
+	}
+	pageCount := (first.Total + limit - 1) / limit
+	pages := make([][]any, pageCount)
+	pages[0] = first.Items
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+		loaded   = len(first.Items)
+		skips    = make(chan int)
+	)
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for skip := range skips {
+				page, err := getPage(newCollection, skip)
+				mu.Lock()
+				if err != nil {
+					if firstErr == nil {
+						firstErr = err
+					}
+				} else {
+					pages[skip/limit] = page.Items
+					loaded += len(page.Items)
+					log.Printf("Loaded %d of %d items", loaded, first.Total)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	for skip := limit; skip < first.Total; skip += limit {
+		skips <- skip
+	}
+	close(skips)
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	items := make([]any, 0, first.Total)
+	for _, page := range pages {
+		items = append(items, page...)
+	}
+	if len(items) != first.Total {
+		return nil, fmt.Errorf("loaded %d of %d items, the collection changed while loading", len(items), first.Total)
+	}
+	elapsed := time.Since(start)
+	log.Printf("Loaded %d items in %s (%.0f items/s)", len(items), elapsed.Round(time.Millisecond), float64(len(items))/elapsed.Seconds())
+	first.Items = items
+	return first, nil
+}
+
+func getPage(newCollection func() *contentful.Collection, skip int) (*contentful.Collection, error) {
+	col := newCollection()
+	col.Query.Order("sys.id", false)
+	col.Query.Limit(col.Limit)
+	// see GetPages for why the skip is set as a plain parameter
+	col.Query.Equal("skip", skip)
+	return col.Get()
+}
+
+// listAll loads all items of a collection endpoint with Do, page by page with an explicit skip and limit
+// like GetPages. It is used for endpoints the contentful package has no collection for, like the
+// space-wide API keys and roles.
+func listAll[T any](ctx context.Context, cma *contentful.Contentful, path string) ([]T, error) {
+	const limit = 100
+	var items []T
+	for {
+		var page struct {
+			Total int `json:"total"`
+			Items []T `json:"items"`
+		}
+		err := Do(ctx, cma, http.MethodGet, fmt.Sprintf("%s?limit=%d&skip=%d", path, limit, len(items)), nil, &page)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, page.Items...)
+		if len(page.Items) == 0 || len(items) >= page.Total {
+			return items, nil
+		}
+	}
+}
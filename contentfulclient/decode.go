@@ -0,0 +1,68 @@
+package contentfulclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/foomo/contentful"
+)
+
+// DecodeError is an item of a collection that could not be decoded
+type DecodeError struct {
+	ID  string
+	Err error
+}
+
+// DecodeErrors are returned by DecodeItems in strict mode
+type DecodeErrors []DecodeError
+
+func (e DecodeErrors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, decodeError := range e {
+		messages = append(messages, fmt.Sprintf("%s: %v", decodeError.ID, decodeError.Err))
+	}
+	return fmt.Sprintf("%d items could not be decoded: %s", len(e), strings.Join(messages, "; "))
+}
+
+// DecodeItems decodes the items of a collection one by one. Unlike the To... methods of
+// contentful.Collection it does not stop at the first broken item and silently return the ones before it.
+// In strict mode all failures are returned as DecodeErrors, otherwise they are logged and the item is skipped.
+func DecodeItems[T any](col *contentful.Collection) ([]T, error) {
+	items := make([]T, 0, len(col.Items))
+	var decodeErrors DecodeErrors
+	for _, item := range col.Items {
+		var decoded T
+		byt, err := json.Marshal(item)
+		if err == nil {
+			err = json.Unmarshal(byt, &decoded)
+		}
+		if err != nil {
+			decodeErrors = append(decodeErrors, DecodeError{ID: itemID(item), Err: err})
+			continue
+		}
+		items = append(items, decoded)
+	}
+	if len(decodeErrors) == 0 {
+		return items, nil
+	}
+	if config.StrictDecode {
+		return nil, decodeErrors
+	}
+	for _, decodeError := range decodeErrors {
+		log.Printf("Warning: skipping %s which could not be decoded: %v", decodeError.ID, decodeError.Err)
+	}
+	return items, nil
+}
+
+func itemID(item any) string {
+	if itemMap, ok := item.(map[string]any); ok {
+		if sys, ok := itemMap["sys"].(map[string]any); ok {
+			if id, ok := sys["id"].(string); ok {
+				return id
+			}
+		}
+	}
+	return "unknown item"
+}
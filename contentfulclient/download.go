@@ -0,0 +1,30 @@
+package contentfulclient
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Download writes the file at fileURL to w, protocol relative asset URLs are fetched with https
+func Download(ctx context.Context, fileURL string, w io.Writer) error {
+	if strings.HasPrefix(fileURL, "//") {
+		fileURL = "https:" + fileURL
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fileURL, nil)
+	if err != nil {
+		return err
+	}
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("could not download %s: %s", fileURL, res.Status)
+	}
+	_, err = io.Copy(w, res.Body)
+	return err
+}
@@ -0,0 +1,124 @@
+package contentfulclient
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/foomo/contentful"
+)
+
+// RunContentTypeID is the reserved content type of the run history entries
+const RunContentTypeID = "commanderRun"
+
+type target struct {
+	cma         *contentful.Contentful
+	spaceID     string
+	environment string
+	owner       string
+}
+
+var (
+	targetsMutex sync.Mutex
+	// targets are the environments a command was preflighted to change
+	targets = map[string]target{}
+
+	writeCount   int64
+	publishCount int64
+)
+
+func rememberTarget(cma *contentful.Contentful, spaceID, environment, owner string) {
+	targetsMutex.Lock()
+	defer targetsMutex.Unlock()
+	targets[spaceID+"/"+environment] = target{cma: cma, spaceID: spaceID, environment: environment, owner: owner}
+}
+
+// countingTransport counts the successful write and publish requests for the summary of a run
+type countingTransport struct {
+	next http.RoundTripper
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	res, err := t.next.RoundTrip(req)
+	if err != nil || res.StatusCode < 200 || res.StatusCode >= 300 {
+		return res, err
+	}
+	switch GetRequestClass(req) {
+	case RequestClassWrite:
+		atomic.AddInt64(&writeCount, 1)
+	case RequestClassPublish:
+		atomic.AddInt64(&publishCount, 1)
+	}
+	return res, err
+}
+
+// RecordRun adds an entry of the reserved content type commanderRun to every environment the command
+// was allowed to change, if the history is enabled. The entries stay drafts, they are meant for editors.
+func RecordRun(ctx context.Context, command string, params []string, startedAt time.Time, runErr error) {
+	if !config.History {
+		return
+	}
+	targetsMutex.Lock()
+	defer targetsMutex.Unlock()
+	result := "success"
+	summary := fmt.Sprintf("%d write and %d publish requests", atomic.LoadInt64(&writeCount), atomic.LoadInt64(&publishCount))
+	if runErr != nil {
+		result = "failed"
+		summary += ", error: " + runErr.Error()
+	}
+	finishedAt := time.Now().UTC()
+	for key, t := range targets {
+		err := recordRun(ctx, t, map[string]string{
+			"runId":      RunID,
+			"command":    command,
+			"params":     strings.Join(params, " "),
+			"user":       t.owner,
+			"startedAt":  startedAt.UTC().Format(time.RFC3339),
+			"finishedAt": finishedAt.Format(time.RFC3339),
+			"result":     result,
+			"summary":    summary,
+		})
+		if err != nil {
+			log.Printf("Could not record the run in the history of %s: %v", key, err)
+			continue
+		}
+		log.Printf("Recorded run %s in the history of %s", RunID, key)
+	}
+}
+
+func recordRun(ctx context.Context, t target, values map[string]string) error {
+	basePath := fmt.Sprintf("/spaces/%s/environments/%s", t.spaceID, t.environment)
+	err := ensureReservedContentType(ctx, t.cma, basePath, RunContentTypeID, map[string]any{
+		"name":         "Commander run",
+		"description":  "Reserved by contentfulcommander to record the commands that changed this environment",
+		"displayField": "command",
+		"fields": []map[string]any{
+			reservedField("runId", "Run ID", "Symbol"),
+			reservedField("command", "Command", "Symbol"),
+			reservedField("params", "Parameters", "Text"),
+			reservedField("user", "User", "Symbol"),
+			reservedField("startedAt", "Started at", "Date"),
+			reservedField("finishedAt", "Finished at", "Date"),
+			reservedField("result", "Result", "Symbol"),
+			reservedField("summary", "Summary", "Text"),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("could not set up the run content type: %v", err)
+	}
+	locale, err := getDefaultLocale(ctx, t.cma, basePath)
+	if err != nil {
+		return err
+	}
+	fields := map[string]map[string]string{}
+	for fieldID, value := range values {
+		fields[fieldID] = map[string]string{locale: value}
+	}
+	return DoWithHeaders(ctx, t.cma, http.MethodPost, basePath+"/entries",
+		map[string]string{"X-Contentful-Content-Type": RunContentTypeID}, map[string]any{"fields": fields}, nil)
+}
@@ -0,0 +1,150 @@
+package contentfulclient
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/foomo/contentful"
+)
+
+const (
+	// LockContentTypeID is the reserved content type of the lock entries, there is one lock entry with
+	// the same ID per environment
+	LockContentTypeID = "commanderLock"
+	lockEntryID       = "commanderLock"
+	lockTTL           = time.Hour
+	// maxSymbolLength is the most characters a Symbol field takes
+	maxSymbolLength = 256
+)
+
+// RunID identifies this invocation of the commander in locks
+var RunID = newRunID()
+
+var (
+	locksMutex sync.Mutex
+	locks      = map[string]*lock{}
+)
+
+type lock struct {
+	cma     *contentful.Contentful
+	path    string
+	version int
+}
+
+type lockEntry struct {
+	Sys struct {
+		Version int `json:"version"`
+	} `json:"sys"`
+	Fields map[string]map[string]string `json:"fields"`
+}
+
+func newRunID() string {
+	byt := make([]byte, 4)
+	_, _ = rand.Read(byt)
+	return time.Now().UTC().Format("20060102T150405") + "-" + hex.EncodeToString(byt)
+}
+
+// acquireLock locks an environment for this run or returns an error naming the run that holds the lock.
+// Locks of crashed runs expire after lockTTL. Creating and updating the lock entry with its version makes
+// sure that only one of two concurrent runs gets the lock.
+func acquireLock(ctx context.Context, cma *contentful.Contentful, spaceID, environment, owner string) error {
+	locksMutex.Lock()
+	defer locksMutex.Unlock()
+	key := spaceID + "/" + environment
+	if _, ok := locks[key]; ok {
+		return nil
+	}
+	basePath := fmt.Sprintf("/spaces/%s/environments/%s", spaceID, environment)
+	err := ensureLockContentType(ctx, cma, basePath)
+	if err != nil {
+		return fmt.Errorf("could not set up the lock content type: %v", err)
+	}
+	locale, err := getDefaultLocale(ctx, cma, basePath)
+	if err != nil {
+		return err
+	}
+	path := basePath + "/entries/" + lockEntryID
+	headers := map[string]string{"X-Contentful-Content-Type": LockContentTypeID}
+	var existing lockEntry
+	err = Do(ctx, cma, http.MethodGet, path, nil, &existing)
+	switch {
+	case err == nil:
+		runID := existing.Fields["runId"][locale]
+		expiresAt, errParse := time.Parse(time.RFC3339, existing.Fields["expiresAt"][locale])
+		if runID != "" && errParse == nil && time.Now().Before(expiresAt) {
+			return fmt.Errorf("environment %s of space %s is locked by run %s of %s running %q until %s",
+				environment, spaceID, runID, existing.Fields["owner"][locale], existing.Fields["command"][locale],
+				expiresAt.Local().Format(time.RFC1123))
+		}
+		headers["X-Contentful-Version"] = strconv.Itoa(existing.Sys.Version)
+	case !isStatus(err, http.StatusNotFound):
+		return fmt.Errorf("could not get the lock of %s: %v", key, err)
+	}
+	now := time.Now().UTC()
+	payload := map[string]any{"fields": map[string]map[string]string{
+		"runId":      {locale: RunID},
+		"owner":      {locale: owner},
+		"command":    {locale: truncateSymbol(strings.Join(os.Args[1:], " "))},
+		"acquiredAt": {locale: now.Format(time.RFC3339)},
+		"expiresAt":  {locale: now.Add(lockTTL).Format(time.RFC3339)},
+	}}
+	var acquired lockEntry
+	err = DoWithHeaders(ctx, cma, http.MethodPut, path, headers, payload, &acquired)
+	if isStatus(err, http.StatusConflict) {
+		return fmt.Errorf("environment %s of space %s was locked by another run at the same time", environment, spaceID)
+	}
+	if err != nil {
+		return fmt.Errorf("could not lock %s: %v", key, err)
+	}
+	locks[key] = &lock{cma: cma, path: path, version: acquired.Sys.Version}
+	log.Printf("Locked %s for run %s", key, RunID)
+	return nil
+}
+
+// ReleaseLocks deletes the lock entries acquired by this run
+func ReleaseLocks(ctx context.Context) {
+	locksMutex.Lock()
+	defer locksMutex.Unlock()
+	for key, l := range locks {
+		err := DoWithHeaders(ctx, l.cma, http.MethodDelete, l.path, versionHeader(float64(l.version)), nil, nil)
+		if err != nil {
+			log.Printf("Could not release the lock of %s, it expires on its own: %v", key, err)
+		} else {
+			log.Printf("Released the lock of %s", key)
+		}
+		delete(locks, key)
+	}
+}
+
+func ensureLockContentType(ctx context.Context, cma *contentful.Contentful, basePath string) error {
+	return ensureReservedContentType(ctx, cma, basePath, LockContentTypeID, map[string]any{
+		"name":         "Commander lock",
+		"description":  "Reserved by contentfulcommander to keep concurrent runs from changing the same environment",
+		"displayField": "runId",
+		"fields": []map[string]any{
+			reservedField("runId", "Run ID", "Symbol"),
+			reservedField("owner", "Owner", "Symbol"),
+			reservedField("command", "Command", "Symbol"),
+			reservedField("acquiredAt", "Acquired at", "Date"),
+			reservedField("expiresAt", "Expires at", "Date"),
+		},
+	})
+}
+
+// truncateSymbol shortens long command lines, which would otherwise fail the validation of the Symbol field
+func truncateSymbol(value string) string {
+	runes := []rune(value)
+	if len(runes) <= maxSymbolLength {
+		return value
+	}
+	return string(runes[:maxSymbolLength-3]) + "..."
+}
@@ -0,0 +1,134 @@
+package contentfulclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/foomo/contentful"
+)
+
+type Operation string
+
+const (
+	OperationRead    Operation = "read"
+	OperationUpdate  Operation = "update"
+	OperationPublish Operation = "publish"
+	OperationArchive Operation = "archive"
+	OperationDelete  Operation = "delete"
+	OperationModel   Operation = "model"
+	OperationApps    Operation = "apps"
+)
+
+// Preflight verifies that the management token can access the space and environment before a command
+// starts working on it. For any planned operation other than read the environment must not be protected,
+// and protected environments cannot be published to during the publish blackout.
+// Role permissions can only be read by space admins, for other users a warning is logged instead.
+// If locking is configured, mutating operations also lock the environment until ReleaseLocks is called.
+// The environment is remembered for RecordRun.
+func Preflight(ctx context.Context, cma *contentful.Contentful, spaceID, environment string, operations ...Operation) error {
+	email, err := checkSpace(ctx, cma, spaceID)
+	if err != nil {
+		return err
+	}
+	err = Do(ctx, cma, http.MethodGet, fmt.Sprintf("/spaces/%s/environments/%s", spaceID, environment), nil, nil)
+	if err != nil {
+		if isStatus(err, http.StatusNotFound) || isStatus(err, http.StatusForbidden) {
+			return fmt.Errorf("environment %s does not exist in space %s or %s has no access to it", environment, spaceID, email)
+		}
+		return fmt.Errorf("could not get environment %s of space %s: %v", environment, spaceID, err)
+	}
+	mutating := mutatingOperations(operations)
+	for _, operation := range operations {
+		if operation == OperationPublish {
+			err = checkPublishBlackout(spaceID, environment, time.Now())
+			if err != nil {
+				return err
+			}
+		}
+	}
+	if len(mutating) == 0 {
+		return nil
+	}
+	err = CheckMutable(spaceID, environment)
+	if err != nil {
+		return err
+	}
+	err = checkRole(ctx, cma, spaceID, email, mutating)
+	if err != nil {
+		return err
+	}
+	rememberTarget(cma, spaceID, environment, email)
+	if config.Lock {
+		return acquireLock(ctx, cma, spaceID, environment, email)
+	}
+	return nil
+}
+
+// PreflightSpace is the preflight check for commands that change settings of the space like API keys or
+// roles. These belong to no environment, so environment protection, locks and the run history do not apply.
+func PreflightSpace(ctx context.Context, cma *contentful.Contentful, spaceID string, operations ...Operation) error {
+	email, err := checkSpace(ctx, cma, spaceID)
+	if err != nil {
+		return err
+	}
+	mutating := mutatingOperations(operations)
+	if len(mutating) == 0 {
+		return nil
+	}
+	return checkRole(ctx, cma, spaceID, email, mutating)
+}
+
+// checkSpace verifies the management token and the access to the space, it returns the email of the user
+func checkSpace(ctx context.Context, cma *contentful.Contentful, spaceID string) (string, error) {
+	var user struct {
+		Email string `json:"email"`
+	}
+	err := Do(ctx, cma, http.MethodGet, "/users/me", nil, &user)
+	if err != nil {
+		if isStatus(err, http.StatusUnauthorized) {
+			return "", errors.New("the management token is invalid or expired, log in again with: contentful login")
+		}
+		return "", fmt.Errorf("could not get the current user: %v", err)
+	}
+	err = Do(ctx, cma, http.MethodGet, "/spaces/"+spaceID, nil, nil)
+	if err != nil {
+		if isStatus(err, http.StatusNotFound) || isStatus(err, http.StatusForbidden) {
+			return "", fmt.Errorf("space %s does not exist or %s has no access to it", spaceID, user.Email)
+		}
+		return "", fmt.Errorf("could not get space %s: %v", spaceID, err)
+	}
+	return user.Email, nil
+}
+
+func mutatingOperations(operations []Operation) []string {
+	var mutating []string
+	for _, operation := range operations {
+		if operation != OperationRead {
+			mutating = append(mutating, string(operation))
+		}
+	}
+	return mutating
+}
+
+// checkRole logs a warning for users who are no space admins, as only admins can read role permissions
+func checkRole(ctx context.Context, cma *contentful.Contentful, spaceID, email string, mutating []string) error {
+	err := Do(ctx, cma, http.MethodGet, fmt.Sprintf("/spaces/%s/space_memberships?limit=1", spaceID), nil, nil)
+	if err != nil {
+		if !isStatus(err, http.StatusForbidden) {
+			return fmt.Errorf("could not check the role of %s in space %s: %v", email, spaceID, err)
+		}
+		log.Printf("%s is not an admin of space %s, make sure the role allows: %s",
+			email, spaceID, strings.Join(mutating, ", "))
+	}
+	return nil
+}
+
+func isStatus(err error, statusCode int) bool {
+	var apiError APIError
+	return errors.As(err, &apiError) && apiError.StatusCode == statusCode
+}
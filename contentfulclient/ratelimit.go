@@ -0,0 +1,61 @@
+package contentfulclient
+
+import (
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	rateLimitMaxRetries   = 5
+	rateLimitDefaultReset = time.Second
+)
+
+// rateLimitTransport retries requests that were rejected with 429 after the number of seconds given in
+// the X-Contentful-RateLimit-Reset header. The contentful package retries these requests itself, but
+// sends the already consumed body again, so they are retried here before the package sees them.
+type rateLimitTransport struct {
+	next http.RoundTripper
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		res, err := t.next.RoundTrip(req)
+		if err != nil || res.StatusCode != http.StatusTooManyRequests || attempt > rateLimitMaxRetries {
+			return res, err
+		}
+		// requests without GetBody cannot be sent again
+		if req.Body != nil && req.GetBody == nil {
+			return res, nil
+		}
+		wait := getRateLimitReset(res.Header)
+		_, _ = io.Copy(io.Discard, res.Body)
+		res.Body.Close()
+		log.Printf("Rate limit exceeded on %s %s, retrying in %s", req.Method, req.URL.Path, wait)
+		timer := time.NewTimer(wait)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+	}
+}
+
+func getRateLimitReset(header http.Header) time.Duration {
+	seconds, err := strconv.Atoi(header.Get("X-Contentful-RateLimit-Reset"))
+	if err != nil || seconds < 1 {
+		return rateLimitDefaultReset
+	}
+	return time.Duration(seconds) * time.Second
+}
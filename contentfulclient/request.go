@@ -0,0 +1,76 @@
+package contentfulclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/foomo/contentful"
+)
+
+// APIError is returned by Do for responses outside the 2xx range
+type APIError struct {
+	StatusCode int
+	ID         string
+	Message    string
+}
+
+func (e APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("contentful API error: %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+	}
+	return fmt.Sprintf("contentful API error: %d %s: %s", e.StatusCode, e.ID, e.Message)
+}
+
+// Do sends a request to the API of the given client for endpoints the contentful package does not cover.
+// The body is sent as JSON if not nil and the response is decoded into v if not nil.
+func Do(ctx context.Context, cma *contentful.Contentful, method, path string, body, v any) error {
+	return DoWithHeaders(ctx, cma, method, path, nil, body, v)
+}
+
+// DoWithHeaders works like Do and sets additional request headers
+func DoWithHeaders(ctx context.Context, cma *contentful.Contentful, method, path string, headers map[string]string, body, v any) error {
+	var reader io.Reader
+	if body != nil {
+		byt, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(byt)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, cma.BaseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	for key, value := range cma.Headers {
+		req.Header.Set(key, value)
+	}
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return newAPIError(res)
+	}
+	if v == nil {
+		return nil
+	}
+	return json.NewDecoder(res.Body).Decode(v)
+}
+
+func newAPIError(res *http.Response) APIError {
+	var errorResponse contentful.ErrorResponse
+	_ = json.NewDecoder(res.Body).Decode(&errorResponse)
+	apiError := APIError{StatusCode: res.StatusCode, Message: errorResponse.Message}
+	if errorResponse.Sys != nil {
+		apiError.ID = errorResponse.Sys.ID
+	}
+	return apiError
+}
@@ -0,0 +1,60 @@
+package contentfulclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/foomo/contentful"
+)
+
+// Reserved content types hold the data the commander itself keeps in an environment, like locks and the
+// run history. They are created and activated on first use.
+
+func reservedField(id, name, fieldType string) map[string]any {
+	return map[string]any{"id": id, "name": name, "type": fieldType}
+}
+
+func ensureReservedContentType(ctx context.Context, cma *contentful.Contentful, basePath, contentTypeID string,
+	contentType map[string]any,
+) error {
+	path := basePath + "/content_types/" + contentTypeID
+	err := Do(ctx, cma, http.MethodGet, path, nil, nil)
+	if !isStatus(err, http.StatusNotFound) {
+		return err
+	}
+	var created struct {
+		Sys struct {
+			Version int `json:"version"`
+		} `json:"sys"`
+	}
+	err = Do(ctx, cma, http.MethodPut, path, contentType, &created)
+	if err != nil {
+		return err
+	}
+	return DoWithHeaders(ctx, cma, http.MethodPut, path+"/published", versionHeader(float64(created.Sys.Version)), nil, nil)
+}
+
+// GetDefaultLocale returns the default locale of the environment of the client
+func GetDefaultLocale(ctx context.Context, cma *contentful.Contentful, spaceID string) (string, error) {
+	return getDefaultLocale(ctx, cma, fmt.Sprintf("/spaces/%s/environments/%s", spaceID, cma.Environment))
+}
+
+func getDefaultLocale(ctx context.Context, cma *contentful.Contentful, basePath string) (string, error) {
+	var collection struct {
+		Items []struct {
+			Code    string `json:"code"`
+			Default bool   `json:"default"`
+		} `json:"items"`
+	}
+	err := Do(ctx, cma, http.MethodGet, basePath+"/locales", nil, &collection)
+	if err != nil {
+		return "", fmt.Errorf("could not get the locales: %v", err)
+	}
+	for _, locale := range collection.Items {
+		if locale.Default {
+			return locale.Code, nil
+		}
+	}
+	return "", fmt.Errorf("no default locale in %s", basePath)
+}
@@ -0,0 +1,70 @@
+package contentfulclient
+
+import (
+	"io"
+	"log"
+	"net/http"
+	"time"
+)
+
+const maxRetryBackoff = 30 * time.Second
+
+// RetryPolicy configures how often requests failing with one of the StatusCodes are retried. The wait
+// before a retry starts with Backoff and doubles with every attempt.
+type RetryPolicy struct {
+	MaxRetries  int
+	Backoff     time.Duration
+	StatusCodes []int
+}
+
+// retryTransport retries requests according to the policy. POST requests are never retried, they create
+// entities and a failed response does not tell whether the entity was created anyway.
+type retryTransport struct {
+	next   http.RoundTripper
+	policy RetryPolicy
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	backoff := t.policy.Backoff
+	for attempt := 0; ; attempt++ {
+		res, err := t.next.RoundTrip(req)
+		if err != nil || !t.isRetryable(req, res.StatusCode) || attempt >= t.policy.MaxRetries {
+			return res, err
+		}
+		_, _ = io.Copy(io.Discard, res.Body)
+		res.Body.Close()
+		log.Printf("%s %s failed with %d, retry %d of %d in %s", req.Method, req.URL.Path, res.StatusCode,
+			attempt+1, t.policy.MaxRetries, backoff)
+		timer := time.NewTimer(backoff)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+		backoff *= 2
+		if backoff > maxRetryBackoff {
+			backoff = maxRetryBackoff
+		}
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+	}
+}
+
+func (t *retryTransport) isRetryable(req *http.Request, statusCode int) bool {
+	if req.Method == http.MethodPost || (req.Body != nil && req.GetBody == nil) {
+		return false
+	}
+	for _, retryable := range t.policy.StatusCodes {
+		if statusCode == retryable {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,38 @@
+package contentfulclient
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/foomo/contentful"
+
+	"github.com/foomo/contentfulcommander/model"
+)
+
+type SnapshotSys struct {
+	ID           string              `json:"id"`
+	Type         string              `json:"type"`
+	SnapshotType string              `json:"snapshotType"`
+	CreatedAt    string              `json:"createdAt"`
+	CreatedBy    *model.ReferenceSys `json:"createdBy,omitempty"`
+}
+
+// Snapshot is a version of an entry, Contentful creates one every time an entry is published
+type Snapshot struct {
+	Sys      SnapshotSys       `json:"sys"`
+	Snapshot *contentful.Entry `json:"snapshot"`
+}
+
+// GetEntrySnapshots returns the snapshots of an entry in the environment of the client, oldest first
+func GetEntrySnapshots(ctx context.Context, cma *contentful.Contentful, spaceID, entryID string) ([]Snapshot, error) {
+	snapshots, err := listAll[Snapshot](ctx, cma,
+		fmt.Sprintf("/spaces/%s/environments/%s/entries/%s/snapshots", spaceID, cma.Environment, entryID))
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].Sys.CreatedAt < snapshots[j].Sys.CreatedAt
+	})
+	return snapshots, nil
+}
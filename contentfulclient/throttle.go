@@ -0,0 +1,83 @@
+package contentfulclient
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RequestClass groups requests that share a rate limit
+type RequestClass string
+
+const (
+	RequestClassRead    RequestClass = "read"
+	RequestClassWrite   RequestClass = "write"
+	RequestClassPublish RequestClass = "publish"
+)
+
+// GetRequestClass tells publish and unpublish requests apart from other writes and from reads
+func GetRequestClass(req *http.Request) RequestClass {
+	switch {
+	case req.Method == http.MethodGet || req.Method == http.MethodHead:
+		return RequestClassRead
+	case strings.HasSuffix(req.URL.Path, "/published"):
+		return RequestClassPublish
+	default:
+		return RequestClassWrite
+	}
+}
+
+type limiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+// wait blocks until the next request is allowed
+func (l *limiter) wait(req *http.Request) error {
+	l.mu.Lock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	delay := l.next.Sub(now)
+	l.next = l.next.Add(l.interval)
+	l.mu.Unlock()
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-req.Context().Done():
+		return req.Context().Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// throttlingTransport spaces out requests so that each request class stays below its rate limit
+type throttlingTransport struct {
+	next     http.RoundTripper
+	limiters map[RequestClass]*limiter
+}
+
+func newThrottlingTransport(next http.RoundTripper, rateLimits map[RequestClass]float64) *throttlingTransport {
+	t := &throttlingTransport{next: next, limiters: map[RequestClass]*limiter{}}
+	for class, perSecond := range rateLimits {
+		if perSecond > 0 {
+			t.limiters[class] = &limiter{interval: time.Duration(float64(time.Second) / perSecond)}
+		}
+	}
+	return t
+}
+
+func (t *throttlingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if l, ok := t.limiters[GetRequestClass(req)]; ok {
+		if err := l.wait(req); err != nil {
+			return nil, err
+		}
+	}
+	return t.next.RoundTrip(req)
+}
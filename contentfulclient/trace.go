@@ -0,0 +1,91 @@
+package contentfulclient
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httputil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	redactAuthorization = regexp.MustCompile(`(?im)^(Authorization: Bearer ).+$`)
+	redactAccessToken   = regexp.MustCompile(`("accessToken"\s*:\s*")[^"]*`)
+)
+
+// tracingTransport dumps every request and response pair to the log or, if dir is set, into one file per
+// request in dir. Tokens are redacted and only JSON bodies are dumped, uploads and downloads of asset
+// files are left out.
+type tracingTransport struct {
+	next    http.RoundTripper
+	dir     string
+	counter int64
+}
+
+// newTracingTransport sets up tracing for a CONTENTFUL_DEBUG value, which is either a directory to write
+// the dumps to or any of 1, true and log to write them to the log
+func newTracingTransport(next http.RoundTripper, debug string) (*tracingTransport, error) {
+	t := &tracingTransport{next: next}
+	switch strings.ToLower(debug) {
+	case "1", "true", "log":
+		return t, nil
+	}
+	err := os.MkdirAll(debug, 0o700)
+	if err != nil {
+		return nil, fmt.Errorf("could not create debug directory %s: %v", debug, err)
+	}
+	t.dir = debug
+	return t, nil
+}
+
+func (t *tracingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	number := atomic.AddInt64(&t.counter, 1)
+	requestDump, err := httputil.DumpRequestOut(req, isJSON(req.Header))
+	if err != nil {
+		return nil, err
+	}
+	start := time.Now()
+	res, errRoundTrip := t.next.RoundTrip(req)
+	duration := time.Since(start)
+	var trace strings.Builder
+	fmt.Fprintf(&trace, "### %s %s took %s\n%s\n", req.Method, req.URL, duration, requestDump)
+	status := "error"
+	if errRoundTrip != nil {
+		fmt.Fprintf(&trace, "\n### error: %v\n", errRoundTrip)
+	} else {
+		status = fmt.Sprint(res.StatusCode)
+		responseDump, err := httputil.DumpResponse(res, isJSON(res.Header))
+		if err != nil {
+			// the body may be partially read already, so the response cannot be used anymore
+			_ = res.Body.Close()
+			return nil, fmt.Errorf("could not dump the response of %s %s: %v", req.Method, req.URL, err)
+		}
+		fmt.Fprintf(&trace, "\n### response\n%s\n", responseDump)
+	}
+	dump := redact(trace.String())
+	if t.dir == "" {
+		log.Print(dump)
+		return res, errRoundTrip
+	}
+	fileName := filepath.Join(t.dir, fmt.Sprintf("%06d-%s-%s.http", number, req.Method, status))
+	if err := os.WriteFile(fileName, []byte(dump), 0o600); err != nil {
+		log.Printf("Could not write debug trace %s: %v", fileName, err)
+	}
+	return res, errRoundTrip
+}
+
+// isJSON is true for the JSON content types of the Contentful APIs and for requests without a body
+func isJSON(header http.Header) bool {
+	contentType := header.Get("Content-Type")
+	return contentType == "" || strings.Contains(contentType, "json")
+}
+
+func redact(dump string) string {
+	dump = redactAuthorization.ReplaceAllString(dump, "${1}[REDACTED]")
+	return redactAccessToken.ReplaceAllString(dump, "${1}[REDACTED]")
+}
@@ -0,0 +1,68 @@
+package contentfulclient
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+)
+
+var httpClient = http.DefaultClient
+
+// SetHTTPClient replaces the HTTP client used for all requests to Contentful. Call it after Configure,
+// whose connection settings it overrides, and before GetCMA
+func SetHTTPClient(client *http.Client) {
+	httpClient = client
+}
+
+func newHTTPClient(c Config) (*http.Client, error) {
+	transport, ok := http.DefaultTransport.(*http.Transport)
+	if !ok {
+		return nil, errors.New("default transport is not an *http.Transport")
+	}
+	transport = transport.Clone()
+	if c.Proxy != "" {
+		proxyURL, err := url.Parse(c.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy URL %q: %v", c.Proxy, err)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+	if c.CACertFile != "" {
+		pem, err := os.ReadFile(c.CACertFile)
+		if err != nil {
+			return nil, err
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", c.CACertFile)
+		}
+		transport.TLSClientConfig = &tls.Config{
+			RootCAs:    pool,
+			MinVersion: tls.VersionTLS12,
+		}
+	}
+	var roundTripper http.RoundTripper = transport
+	if c.Debug != "" {
+		tracing, err := newTracingTransport(roundTripper, c.Debug)
+		if err != nil {
+			return nil, err
+		}
+		roundTripper = tracing
+	}
+	roundTripper = &rateLimitTransport{next: roundTripper}
+	if c.Retry.MaxRetries > 0 {
+		roundTripper = &retryTransport{next: roundTripper, policy: c.Retry}
+	}
+	roundTripper = &countingTransport{next: roundTripper}
+	if len(c.RateLimits) > 0 {
+		roundTripper = newThrottlingTransport(roundTripper, c.RateLimits)
+	}
+	return &http.Client{Transport: roundTripper}, nil
+}
@@ -0,0 +1,46 @@
+package contentfulclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/foomo/contentful"
+)
+
+// UploadBaseURL is the host of the upload API, binaries cannot be sent to the management API
+var UploadBaseURL = "https://upload.contentful.com"
+
+// Upload sends a binary to the upload API and returns the ID of the upload. Uploads expire after a day
+// unless an asset is created from them and processed.
+func Upload(ctx context.Context, cma *contentful.Contentful, spaceID string, data []byte) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/spaces/%s/uploads", UploadBaseURL, spaceID),
+		bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+	for key, value := range cma.Headers {
+		req.Header.Set(key, value)
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return "", newAPIError(res)
+	}
+	var upload struct {
+		Sys struct {
+			ID string `json:"id"`
+		} `json:"sys"`
+	}
+	err = json.NewDecoder(res.Body).Decode(&upload)
+	if err != nil {
+		return "", err
+	}
+	return upload.Sys.ID, nil
+}
@@ -0,0 +1,68 @@
+package contentfulclient
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// PublishBlackout is a daily time window in which nothing may be published to the protected environments
+type PublishBlackout struct {
+	// Start and End are the minutes after midnight, a window with End before Start spans midnight
+	Start    int
+	End      int
+	Location *time.Location
+}
+
+// ParsePublishBlackout reads a window like 09:00-18:00 in the time zone named by location
+func ParsePublishBlackout(window, location string) (*PublishBlackout, error) {
+	if window == "" {
+		return nil, nil
+	}
+	loc, err := time.LoadLocation(location)
+	if err != nil {
+		return nil, fmt.Errorf("unknown time zone %s: %v", location, err)
+	}
+	parts := strings.Split(window, "-")
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("publish blackout %s must look like 09:00-18:00", window)
+	}
+	blackout := &PublishBlackout{Location: loc}
+	for i, part := range parts {
+		clock, err := time.Parse("15:04", strings.TrimSpace(part))
+		if err != nil {
+			return nil, fmt.Errorf("publish blackout %s must look like 09:00-18:00: %v", window, err)
+		}
+		minutes := clock.Hour()*60 + clock.Minute()
+		if i == 0 {
+			blackout.Start = minutes
+		} else {
+			blackout.End = minutes
+		}
+	}
+	return blackout, nil
+}
+
+// Contains is true if t lies within the window
+func (b *PublishBlackout) Contains(t time.Time) bool {
+	local := t.In(b.Location)
+	minutes := local.Hour()*60 + local.Minute()
+	if b.Start <= b.End {
+		return minutes >= b.Start && minutes < b.End
+	}
+	return minutes >= b.Start || minutes < b.End
+}
+
+func (b *PublishBlackout) String() string {
+	return fmt.Sprintf("%02d:%02d and %02d:%02d %s", b.Start/60, b.Start%60, b.End/60, b.End%60, b.Location)
+}
+
+// checkPublishBlackout returns an error if publishing to a protected environment is not allowed right now,
+// which unlike the protection itself cannot be overridden with force
+func checkPublishBlackout(spaceID, environment string, now time.Time) error {
+	if config.PublishBlackout == nil || !config.PublishBlackout.Contains(now) || !isProtected(environment) {
+		return nil
+	}
+	return fmt.Errorf("publishing to environment %s of space %s is not allowed between %s", environment, spaceID,
+		config.PublishBlackout)
+}
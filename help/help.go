@@ -18,27 +18,302 @@ error: you need to be logged in to Contentful to use contentfulcommander
 func GetHelp(args []string) {
 	if len(args) == 0 {
 		fmt.Println(`
-usage: contentfulcommander command [params]
+usage: contentfulcommander [-environment name] [-protected env1,env2] [-force] [-strict]
+                           [-proxy url] [-cacert file] [-useragent name/version]
+                           [-readrate n] [-writerate n] [-publishrate n] [-concurrency n]
+                           [-retries n] [-retrybackoff 1s] [-retrystatus 500,502,503,504]
+                           [-publishblackout 09:00-18:00] [-timezone Europe/Berlin]
+                           [-previewurl template] [-previewlocale locale]
+                           [-lock] [-history] command [params]
+
+Spaces given without an environment use the one set with 'environment', which defaults to master.
+Commands that change content refuse to run on the 'protected' environments (master by default)
+unless 'force' is passed. During the daily 'publishblackout' window in 'timezone', commands that publish
+refuse to run on protected environments, even with 'force'. Entries, assets and content types that
+cannot be decoded are skipped with a warning, with 'strict' the command fails and lists them instead.
+Requests go through the 'proxy' if given, otherwise the HTTPS_PROXY environment variable is honoured,
+and 'cacert' adds trusted certificates for TLS intercepting corporate proxies.
+The rate flags limit the requests per second for reads, writes and publishing separately, e.g. to stay
+below the stricter publish limits in long runs without slowing down loading.
+With 'concurrency' greater than one, the pages of large collections are loaded concurrently, still within
+the read rate. Requests rejected with 429 by the rate limit of Contentful are retried after the reset time
+the API sends with the rejection. Requests failing with one of the 'retrystatus' codes are retried up to
+'retries' times, waiting 'retrybackoff' before the first retry and twice as long before each further one.
+POST requests are not retried because they may have created an entity despite the error.
+With 'lock' commands that change content lock the environment with an entry of the reserved content type
+commanderLock, which is created if needed. Other runs with 'lock' then fail, naming the run that holds the
+lock, until it is released at the end of the command or expires after an hour.
+With 'history' every run of a command that changes content is recorded as a draft entry of the reserved
+content type commanderRun in the environments it changed, with parameters, user, result and the number of
+write and publish requests.
+Reports that list entries add a preview link rendered from 'previewurl', in which {id}, {contentType} and
+{locale} are replaced with the entry ID, its content type and 'previewlocale', and {fields.slug} with the
+value of the field slug in that locale. Without 'previewlocale' the first locale of the entry is used.
+Set CONTENTFUL_DEBUG=1 to log every request and response with redacted tokens, or set it to a
+directory to write one file per request there.
 
 Supported values for 'command' are:
 
 help [command] - Display this help screen or the 'command' specific one
+apikeys - List, create and update delivery API keys of a space
+apps - List, install and uninstall apps of an environment
+brokenlinks - Find links to entries and assets that no longer exist
 chid - Change the Sys.ID of an entry
-modeldiff - Compare two content models across spaces and environments`)
+churn - Report how often the entries of each content type change
+contentdiff - Compare the entries of two spaces and environments field by field
+deepcopy - Copy entries with everything they link to into another space
+docgen - Render the content model to Markdown or HTML
+download - Download the files of all assets to a directory
+export - Dump all content of a space to JSON or NDJSON files
+freshness - Show when each field and locale of entries was last changed
+getentry - Print an entry as JSON
+import - Restore a dump written by export into a space
+linkvalidations - Propose link content type validations based on the existing links
+localeimpact - Show which content would be lost by removing a locale
+modeldiff - Compare two content models across spaces and environments
+orphans - List entries that no other entry links to and optionally retire them
+putentry - Create or update an entry from a JSON file
+relink - Move all references from one entry or asset to another
+republish - Re-publish all entries and assets that have unpublished changes
+resourcelinks - Find cross-space references and turn them into local ones
+roles - List, create and update the roles of a space
+snapshots - List, show and restore previous versions of an entry
+staledrafts - Report and archive old drafts that nothing links to
+upload - Create assets from local files or URLs
+usage - Show record counts and API usage of a space
+xliff - Export and import translations as XLIFF files`)
 		os.Exit(0)
 	}
 	switch args[0] {
+	case "apikeys":
+		fmt.Println(`usage: contentfulcommander apikeys list space
+       contentfulcommander apikeys create [-description text] [-environments env1,env2] space name
+       contentfulcommander apikeys update [-description text] [-environments env1,env2] space apikeyid
+
+Manages the delivery API keys of a space. A new key gets access to the environments passed with
+'environments' or to the environment of the 'space' parameter, its access token is printed once created.
+Updating a key replaces its environments with the ones passed. API keys belong to the space, so protected
+environments and locks do not apply and no run is recorded.
+The 'space' parameter is specified in the form spaceid[/environment].`)
+	case "apps":
+		fmt.Println(`usage: contentfulcommander apps list space
+       contentfulcommander apps definitions space
+       contentfulcommander apps install space appdefinitionid [file]
+       contentfulcommander apps uninstall space appdefinitionid
+
+Manages the app installations of an environment, e.g. to install the editor apps when bootstrapping a new
+environment. 'list' prints the installed apps with their parameters and 'definitions' the custom apps of
+the organization the space belongs to. 'install' installs an app or, if it is already installed, replaces
+its parameters with the JSON object in 'file'.
+The 'space' parameter is specified in the form spaceid[/environment].`)
+	case "brokenlinks":
+		fmt.Println(`usage: contentfulcommander brokenlinks [-contenttype id] space
+
+Checks the reference fields and the embedded entries, assets and entry hyperlinks in RichText fields of all
+entries, optionally only those of content type 'contenttype', and reports links to entries and assets that
+do not exist in the space, grouped by content type and field.
+The 'space' parameter is specified in the form spaceid[/environment].`)
 	case "chid":
 		fmt.Println(`
 usage: contentfulcommander space chid oldid newid
+       contentfulcommander chid -file pairs.csv space
 
 Makes a copy of the entry with ID equal to 'newid'. Restores all references and preserves the publishing status.
 The 'oldid' version of the entry is archived unless 'deleteold' is passed. 
+With 'file' all old ID and new ID pairs of the CSV file are changed in one run, loading the entries of the
+space only once. Lines starting with # are skipped.
+The 'space' parameter is specified in the form spaceid[/environment].`)
+	case "churn":
+		fmt.Println(`usage: contentfulcommander churn [-contenttype id] space
+
+Reports the edits and publishes per entry and the median and maximum version for each content type, with a
+histogram of the mean time between edits of its entries. The numbers are derived from the version and
+publish counters and the creation and update dates of the entries, so the mean time between edits is
+spread evenly over the lifetime of an entry. The most volatile content types are listed first.
+The 'space' parameter is specified in the form spaceid[/environment].`)
+	case "contentdiff":
+		fmt.Println(`usage: contentfulcommander contentdiff [-contenttype id] [-ids id1,id2] firstspace secondspace
+
+Compares the entries with the same ID in two spaces, e.g. to verify an environment promotion, and shows the
+entries that only exist on one side and the differences of every field and locale. The entries can be
+limited to the content type 'contenttype' and to the entry IDs in 'ids'.
+The 'firstspace' and 'secondspace' parameters are specified in the form spaceid[/environment].`)
+	case "deepcopy":
+		fmt.Println(`usage: contentfulcommander deepcopy [-existing newid|link|update] [-nopublish] sourcespace targetspace entryid [entryid...]
+
+Copies entries with all entries and assets they link to, directly or indirectly, from 'sourcespace' to
+'targetspace'. Entities keep their IDs where these are free in the target. For taken IDs 'existing'
+decides: 'newid' copies under a new ID, 'link' links to the entity in the target without copying it and
+'update' overwrites existing entries. Existing assets are always linked. Links are changed to point to the
+copies, published entities are published in the target unless 'nopublish' is given. The content types
+must exist in the target, asset files are fetched from the source by Contentful.
+The 'sourcespace' and 'targetspace' parameters are specified in the form spaceid[/environment].`)
+	case "docgen":
+		fmt.Println(`usage: contentfulcommander docgen [-format markdown|html] [-out file] space
+
+Renders the content model of a space with all content types, fields and validations and the relationships
+between content types. Markdown output includes a Mermaid diagram of the relationships. Content types are
+sorted by ID, so the output can be committed and model changes show up in code review.
+The 'space' parameter is specified in the form spaceid[/environment].`)
+	case "download":
+		fmt.Println(`usage: contentfulcommander download [-mimetype group] [-parallel 5] space directory
+
+Downloads the files of all assets, optionally only those of the MIME type group 'mimetype' like image or
+pdfdocument, e.g. to back up media before destructive asset migrations. The files of each locale are
+written to directory/locale/assetid-filename. Existing files are skipped, so an interrupted download can be
+continued by running the command again.
+The 'space' parameter is specified in the form spaceid[/environment].`)
+	case "export":
+		fmt.Println(`usage: contentfulcommander export [-format json|ndjson] space directory
+
+Writes all locales, content types, entries and assets of a space with all locales and sys metadata to
+the files locales, contentTypes, entries and assets in 'directory', e.g. to snapshot an environment
+before running destructive commands. The items are stored exactly as returned by the API.
+The 'space' parameter is specified in the form spaceid[/environment].`)
+	case "freshness":
+		fmt.Println(`usage: contentfulcommander freshness [-source locale] [-outdated] space entryid [entryid...]
+       contentfulcommander freshness -contenttype id [-source locale] [-outdated] space
+
+Uses the publishing snapshots of entries to find out when the value of each field and locale last changed
+and who published that change. With 'source' all other locales changed before the source locale are marked
+as outdated, which helps to find translations that need an update. 'outdated' only lists those.
+The 'space' parameter is specified in the form spaceid[/environment].`)
+	case "getentry":
+		fmt.Println(`usage: contentfulcommander getentry [-out file] space entryid
+
+Prints an entry exactly as the Content Management API returns it, with sys, metadata and all locales, or
+writes it to 'out'. The output can be edited and written back with putentry, or attached to bug reports.
+The 'space' parameter is specified in the form spaceid[/environment].`)
+	case "import":
+		fmt.Println(`usage: contentfulcommander import [-nopublish] directory space
+
+Creates or updates all entries and assets of a dump written by export in 'directory' with their original IDs.
+Assets that do not exist yet are uploaded again from the URLs in the dump and processed. Afterwards the
+published and archived status of the dump is restored unless 'nopublish' is passed, assets first and entries
+after the entries they link to. Only the latest version of each item is in the dump, so items that had
+unpublished changes get these changes published.
+The content types of the entries must already exist in the target space.
+The 'space' parameter is specified in the form spaceid[/environment].`)
+	case "linkvalidations":
+		fmt.Println(`usage: contentfulcommander linkvalidations [-contenttype id] space
+
+Looks at the content types that are actually linked from each Link and Array of Link field to entries and
+proposes a tighter linkContentType validation for fields that allow more content types than are used.
+Links that were created before a validation was added and do not match it are reported as warnings.
+The 'space' parameter is specified in the form spaceid[/environment].`)
+	case "localeimpact":
+		fmt.Println(`usage: contentfulcommander localeimpact [-copyto locale] [-strip] space locale
+
+Deleting a locale in Contentful silently drops all content stored in it. This command reports how many
+entries and assets have data in 'locale', per content type and field. With 'copyto' the data is copied
+to another locale where that one has no value yet, with 'strip' the locale is removed from all entries and
+assets. For assets this covers title, description and file. Both keep the publishing status.
 The 'space' parameter is specified in the form spaceid[/environment].`)
 	case "modeldiff":
-		fmt.Println(`usage: contentfulcommander modeldiff firstspace secondspace
+		fmt.Println(`usage: contentfulcommander modeldiff [-apply] [-dryrun] [-yes] firstspace secondspace
 
 Compares the content model of two spaces and shows the differences. The 'firstspace' and 'secondspace' 
-parameters are specified in the form spaceid[/environment].`)
+parameters are specified in the form spaceid[/environment].
+With 'apply' the content types that are missing or different in 'secondspace' are created or updated
+like in 'firstspace' and activated after asking for confirmation, which 'yes' skips. 'dryrun' only lists
+them. Fields that only exist in 'secondspace' are kept, they have to be omitted and deleted by hand.`)
+	case "orphans":
+		fmt.Println(`usage: contentfulcommander orphans [-contenttype id] [-unpublish] [-archive] [-yes] [-bulk] space
+
+Lists all entries that are not linked from any other entry, including links in RichText fields, optionally
+only those of content type 'contenttype'. Entries linking to themselves count as orphans, archived entries
+are ignored. With 'unpublish' the orphans are unpublished, with 'archive' they are unpublished and archived,
+both after a confirmation unless 'yes' is given. With 'bulk' the orphans are unpublished with bulk actions
+of up to 200 entries. Mind that root entries like pages or settings are usually not linked from anywhere,
+so filter by content type before retiring orphans.
+The 'space' parameter is specified in the form spaceid[/environment].`)
+	case "putentry":
+		fmt.Println(`usage: contentfulcommander putentry -file entry.json [-publish] space
+
+Creates or updates an entry from a JSON file in the format written by getentry. Only the ID and content
+type of the sys in the file are used, the fields and metadata replace those of the entry. The entry is
+re-published if it was published without pending changes before, with 'publish' it is published anyway.
+The 'space' parameter is specified in the form spaceid[/environment].`)
+	case "relink":
+		fmt.Println(`usage: contentfulcommander relink -from id -to id [-field id] [-dryrun] space
+
+Changes every reference to the entry or asset 'from' into a reference to 'to', in reference fields and in
+embedded entries, assets and hyperlinks of RichText fields, optionally only in the field 'field'. Unlike
+chid no IDs change, which makes it the tool for consolidating duplicate components. The publishing status
+of the changed entries is preserved. With 'dryrun' the entries are only listed.
+The 'space' parameter is specified in the form spaceid[/environment].`)
+	case "republish":
+		fmt.Println(`usage: contentfulcommander republish [-dryrun] [-contenttype id] [-skipassets] [-bulk] space
+
+Finds all entries and assets that are published but have a newer draft and publishes them again. Assets
+come first and entries are published after the entries they link to. With 'bulk' they are published with
+bulk actions of up to 200 entities, which needs far fewer requests for large spaces.
+With 'dryrun' the changed entities are only listed. Passing 'contenttype' restricts the run to
+entries of that content type and skips assets.
+The 'space' parameter is specified in the form spaceid[/environment].`)
+	case "resourcelinks":
+		fmt.Println(`usage: contentfulcommander resourcelinks [-target spaceid] [-localize spaceid] space
+
+Lists all cross-space references (resource links) in the entries of a space, optionally only the ones
+pointing into the 'target' space. When the content of another space has been merged into this one, pass
+that space as 'localize' to replace the references into it with plain entry references. The affected
+fields need to be changed to reference fields in the content model first. Publishing status is kept.
+The 'space' parameter is specified in the form spaceid[/environment].`)
+	case "roles":
+		fmt.Println(`usage: contentfulcommander roles list space
+       contentfulcommander roles create space file
+       contentfulcommander roles update space roleid file
+
+Manages the roles of a space. The 'file' holds the role as JSON with name, description, policies and
+permissions, in the same format the Content Management API returns it. Its sys is ignored, so a role
+can be copied from one space to another. Roles belong to the space, so protected environments and locks
+do not apply and no run is recorded.
+The 'space' parameter is specified in the form spaceid.`)
+	case "snapshots":
+		fmt.Println(`usage: contentfulcommander snapshots list space entryid
+       contentfulcommander snapshots show space entryid snapshotid
+       contentfulcommander snapshots restore space entryid snapshotid
+
+Contentful keeps a snapshot of an entry every time it is published. 'list' prints the snapshots of an
+entry with their creation time and author, 'show' prints the fields of one snapshot and 'restore' writes
+them back to the entry, e.g. to undo a bad bulk edit. A restored entry is re-published if it was published
+without pending changes before.
+The 'space' parameter is specified in the form spaceid[/environment].`)
+	case "staledrafts":
+		fmt.Println(`usage: contentfulcommander staledrafts [-days 90] [-grace 30] [-contenttype id] [-archive] space
+
+Lists all never published entries that were not updated for 'days' days and are not referenced by any
+other entry, grouped by the user who last updated them. Drafts that stayed stale for another 'grace'
+days are marked and get archived when 'archive' is passed.
+The 'space' parameter is specified in the form spaceid[/environment].`)
+	case "upload":
+		fmt.Println(`usage: contentfulcommander upload [-id assetid] [-locale locale] [-title title] [-nopublish] space file|url [file|url...]
+
+Creates an asset for every local file or URL. Local files are sent to the upload API, URLs are fetched by
+Contentful. The files are processed and the assets are published once processing is done, unless
+'nopublish' is given. The title defaults to the file name and the locale to the default locale of the
+environment. The IDs of the new assets are printed, 'id' sets the ID when uploading a single file.
+The 'space' parameter is specified in the form spaceid[/environment].`)
+	case "usage":
+		fmt.Println(`usage: contentfulcommander usage [-recordlimit n] [-org organizationid] space
+
+Shows the number of entries, assets, content types and locales of a space. With 'recordlimit' set to the
+record limit of the space plan, the remaining headroom is shown too, which is worth checking before
+running commands that create many entries. Passing the 'org' the space belongs to adds the API request
+usage of the current period.
+The 'space' parameter is specified in the form spaceid[/environment].`)
+	case "xliff":
+		fmt.Println(`usage: contentfulcommander xliff export [-version 1.2|2.0] [-fields f1,f2] [-since date] space contenttype sourcelocale targetlocale file
+       contentfulcommander xliff import space file
+
+Exports the localized Symbol, Text and RichText fields of all entries of a content type to an XLIFF file, one
+file element per entry. RichText fields are split into one unit per text node. Existing target values are
+included so that translators can review them. The import reads back a translated XLIFF 1.2 or 2.0 file and
+writes all non-empty targets to the target locale, preserving the publishing status of every entry.
+RichText units are written into the existing translation if it has the structure of the source, otherwise
+the file must translate every text node of the field.
+With 'since', a date like 2024-01-31 or an RFC 3339 time, only entries updated after it are exported, e.g.
+for nightly translation jobs.
+The 'space' parameter is specified in the form spaceid[/environment].`)
 	}
 }
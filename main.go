@@ -1,15 +1,46 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"flag"
 	"fmt"
 	"log"
 	"os"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/foomo/contentfulcommander/cmd/churn"
+	"github.com/foomo/contentfulcommander/cmd/contentdiff"
+	"github.com/foomo/contentfulcommander/cmd/deepcopy"
+	"github.com/foomo/contentfulcommander/cmd/docgen"
+	"github.com/foomo/contentfulcommander/cmd/download"
+	"github.com/foomo/contentfulcommander/cmd/export"
+	"github.com/foomo/contentfulcommander/cmd/freshness"
+	"github.com/foomo/contentfulcommander/cmd/getentry"
+	"github.com/foomo/contentfulcommander/cmd/importer"
+	"github.com/foomo/contentfulcommander/cmd/linkvalidations"
+	"github.com/foomo/contentfulcommander/cmd/localeimpact"
 	"github.com/foomo/contentfulcommander/cmd/modeldiff"
 
+	"github.com/foomo/contentfulcommander/cmd/apikeys"
+	"github.com/foomo/contentfulcommander/cmd/apps"
+	"github.com/foomo/contentfulcommander/cmd/brokenlinks"
 	"github.com/foomo/contentfulcommander/cmd/chid"
+	"github.com/foomo/contentfulcommander/cmd/common"
+	"github.com/foomo/contentfulcommander/cmd/orphans"
+	"github.com/foomo/contentfulcommander/cmd/putentry"
+	"github.com/foomo/contentfulcommander/cmd/relink"
+	"github.com/foomo/contentfulcommander/cmd/republish"
+	"github.com/foomo/contentfulcommander/cmd/resourcelinks"
+	"github.com/foomo/contentfulcommander/cmd/roles"
+	"github.com/foomo/contentfulcommander/cmd/snapshots"
+	"github.com/foomo/contentfulcommander/cmd/staledrafts"
+	"github.com/foomo/contentfulcommander/cmd/upload"
+	"github.com/foomo/contentfulcommander/cmd/usage"
+	"github.com/foomo/contentfulcommander/cmd/xliff"
 	"github.com/foomo/contentfulcommander/contentfulclient"
 	"github.com/foomo/contentfulcommander/help"
 )
@@ -21,7 +52,63 @@ func main() {
 	if cmaKey == "" {
 		help.FatalNoCMAKey()
 	}
+	environment := flag.String("environment", "master", "environment used for spaces given without one")
+	protected := flag.String("protected", "master", "comma separated list of environments that mutating commands refuse to change")
+	force := flag.Bool("force", false, "allow mutating commands on protected environments")
+	proxy := flag.String("proxy", "", "HTTP proxy URL, defaults to the proxy environment variables")
+	caCertFile := flag.String("cacert", "", "PEM file with additional CA certificates to trust")
+	strict := flag.Bool("strict", false, "fail on items that cannot be decoded instead of skipping them")
+	readRate := flag.Float64("readrate", 0, "maximum read requests per second, 0 is unlimited")
+	writeRate := flag.Float64("writerate", 0, "maximum create, update and delete requests per second, 0 is unlimited")
+	publishRate := flag.Float64("publishrate", 0, "maximum publish and unpublish requests per second, 0 is unlimited")
+	history := flag.Bool("history", false, "record each run in the environments it changes")
+	retries := flag.Int("retries", 2, "number of retries of requests failing with a retryable status")
+	retryBackoff := flag.Duration("retrybackoff", time.Second, "wait before the first retry, doubled for every further one")
+	retryStatus := flag.String("retrystatus", "500,502,503,504", "comma separated list of retryable status codes")
+	publishBlackout := flag.String("publishblackout", "", "daily window like 09:00-18:00 in which nothing is published to protected environments")
+	timezone := flag.String("timezone", "Local", "time zone of the publish blackout, e.g. Europe/Berlin")
+	previewURL := flag.String("previewurl", "", "preview URL template for reports, e.g. https://example.com/{locale}/{fields.slug}")
+	previewLocale := flag.String("previewlocale", "", "locale used in preview URLs")
+	lock := flag.Bool("lock", false, "lock environments while changing them so that concurrent runs fail")
+	concurrency := flag.Int("concurrency", 1, "number of pages loaded at the same time")
+	userAgent := flag.String("useragent", "contentfulcommander/"+VERSION, "application user agent sent to Contentful")
 	flag.Parse()
+	retryStatusCodes, err := parseStatusCodes(*retryStatus)
+	if err != nil {
+		log.Fatal(err)
+	}
+	blackout, err := contentfulclient.ParsePublishBlackout(*publishBlackout, *timezone)
+	if err != nil {
+		log.Fatal(err)
+	}
+	err = contentfulclient.Configure(contentfulclient.Config{
+		DefaultEnvironment:    *environment,
+		ProtectedEnvironments: strings.Split(*protected, ","),
+		Force:                 *force,
+		Proxy:                 *proxy,
+		CACertFile:            *caCertFile,
+		UserAgent:             *userAgent,
+		Debug:                 os.Getenv("CONTENTFUL_DEBUG"),
+		StrictDecode:          *strict,
+		Lock:                  *lock,
+		History:               *history,
+		Concurrency:           *concurrency,
+		PublishBlackout:       blackout,
+		Retry: contentfulclient.RetryPolicy{
+			MaxRetries:  *retries,
+			Backoff:     *retryBackoff,
+			StatusCodes: retryStatusCodes,
+		},
+		RateLimits: map[contentfulclient.RequestClass]float64{
+			contentfulclient.RequestClassRead:    *readRate,
+			contentfulclient.RequestClassWrite:   *writeRate,
+			contentfulclient.RequestClassPublish: *publishRate,
+		},
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	common.SetPreviewURL(*previewURL, *previewLocale)
 	args := flag.Args()
 	if len(args) == 0 {
 		help.GetHelp(nil)
@@ -29,20 +116,50 @@ func main() {
 	}
 	command := args[0]
 	params := args[1:]
-	err := runCommand(cmaKey, command, params)
+	startedAt := time.Now()
+	err = runCommandRecovered(cmaKey, command, params)
+	contentfulclient.RecordRun(context.Background(), command, params, startedAt, err)
+	contentfulclient.ReleaseLocks(context.Background())
 	if err != nil {
 		log.Fatal(err)
 	}
 }
 
-func ensureExtraParams(command string, params []string, size int) {
-	if len(params) != size {
-		log.Printf("You need to pass %d parameters to this command but I got %d\n", size, len(params))
+func parseStatusCodes(list string) ([]int, error) {
+	var statusCodes []int
+	for _, value := range strings.Split(list, ",") {
+		if strings.TrimSpace(value) == "" {
+			continue
+		}
+		statusCode, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("invalid status code %q in retrystatus", value)
+		}
+		statusCodes = append(statusCodes, statusCode)
+	}
+	return statusCodes, nil
+}
+
+func ensureMinExtraParams(command string, params []string, size int) {
+	if len(params) < size {
+		log.Printf("You need to pass at least %d parameters to this command but I got %d\n", size, len(params))
 		help.GetHelp([]string{command})
 		os.Exit(1)
 	}
 }
 
+// runCommandRecovered turns a panic of a command into an error, so that crashed runs are recorded and
+// release their locks like failed ones
+func runCommandRecovered(cmaKey, command string, params []string) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("%s panicked: %v\n%s", command, r, debug.Stack())
+			err = fmt.Errorf("%s panicked: %v", command, r)
+		}
+	}()
+	return runCommand(cmaKey, command, params)
+}
+
 func runCommand(cmaKey, command string, params []string) error {
 	switch command {
 	case "help":
@@ -54,12 +171,87 @@ func runCommand(cmaKey, command string, params []string) error {
 	default:
 		client := contentfulclient.GetCMA(cmaKey)
 		switch command {
+		case "apikeys":
+			ensureMinExtraParams(command, params, 2)
+			return apikeys.Run(client, params)
+		case "apps":
+			ensureMinExtraParams(command, params, 2)
+			return apps.Run(client, params)
+		case "brokenlinks":
+			ensureMinExtraParams(command, params, 1)
+			return brokenlinks.Run(client, params)
 		case "chid":
-			ensureExtraParams(command, params, 3)
+			ensureMinExtraParams(command, params, 2)
 			return chid.Run(client, params)
+		case "churn":
+			ensureMinExtraParams(command, params, 1)
+			return churn.Run(client, params)
+		case "contentdiff":
+			ensureMinExtraParams(command, params, 2)
+			return contentdiff.Run(client, params)
+		case "deepcopy":
+			ensureMinExtraParams(command, params, 3)
+			return deepcopy.Run(client, params)
+		case "docgen":
+			ensureMinExtraParams(command, params, 1)
+			return docgen.Run(client, params)
+		case "download":
+			ensureMinExtraParams(command, params, 2)
+			return download.Run(client, params)
+		case "export":
+			ensureMinExtraParams(command, params, 2)
+			return export.Run(client, params)
+		case "freshness":
+			ensureMinExtraParams(command, params, 2)
+			return freshness.Run(client, params)
+		case "getentry":
+			ensureMinExtraParams(command, params, 2)
+			return getentry.Run(client, params)
+		case "import":
+			ensureMinExtraParams(command, params, 2)
+			return importer.Run(client, params)
+		case "linkvalidations":
+			ensureMinExtraParams(command, params, 1)
+			return linkvalidations.Run(client, params)
+		case "localeimpact":
+			ensureMinExtraParams(command, params, 2)
+			return localeimpact.Run(client, params)
 		case "modeldiff":
-			ensureExtraParams(command, params, 2)
+			ensureMinExtraParams(command, params, 2)
 			return modeldiff.Run(client, params)
+		case "orphans":
+			ensureMinExtraParams(command, params, 1)
+			return orphans.Run(client, params)
+		case "putentry":
+			ensureMinExtraParams(command, params, 1)
+			return putentry.Run(client, params)
+		case "relink":
+			ensureMinExtraParams(command, params, 1)
+			return relink.Run(client, params)
+		case "republish":
+			ensureMinExtraParams(command, params, 1)
+			return republish.Run(client, params)
+		case "resourcelinks":
+			ensureMinExtraParams(command, params, 1)
+			return resourcelinks.Run(client, params)
+		case "roles":
+			ensureMinExtraParams(command, params, 2)
+			return roles.Run(client, params)
+		case "snapshots":
+			ensureMinExtraParams(command, params, 3)
+			return snapshots.Run(client, params)
+		case "staledrafts":
+			ensureMinExtraParams(command, params, 1)
+			return staledrafts.Run(client, params)
+		case "upload":
+			ensureMinExtraParams(command, params, 2)
+			return upload.Run(client, params)
+		case "usage":
+			ensureMinExtraParams(command, params, 1)
+			return usage.Run(client, params)
+		case "xliff":
+			ensureMinExtraParams(command, params, 3)
+			return xliff.Run(client, params)
 		default:
 			return errors.New("command not found")
 		}
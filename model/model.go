@@ -67,3 +67,15 @@ type ReferenceSysAttributes struct {
 type ReferenceSys struct {
 	Sys ReferenceSysAttributes `json:"sys,omitempty"`
 }
+
+type ResourceLinkSysAttributes struct {
+	Type     string `json:"type,omitempty"`
+	LinkType string `json:"linkType,omitempty"`
+	URN      string `json:"urn,omitempty"`
+}
+
+// ResourceLink is a cross-space reference, the URN has the form
+// crn:contentful:::content:spaces/{spaceID}[/environments/{environment}]/entries/{entryID}
+type ResourceLink struct {
+	Sys ResourceLinkSysAttributes `json:"sys,omitempty"`
+}